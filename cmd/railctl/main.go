@@ -0,0 +1,166 @@
+// railctl 是监控服务的命令行工具：启动服务、查询 TDX 数据、检查配置等
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/app"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/tdx"
+)
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "", "启动监控服务", runServe},
+		{"board", "[--operator TRA|THSR] [--direction 0|1] <车站>", "显示车站即时看板", runBoard},
+		{"route", "[--operator TRA|THSR] <车次>", "显示车次的停靠站", runRoute},
+		{"stations search", "[--operator TRA|THSR] <关键字>", "按代码或名称搜索车站", runStationsSearch},
+		{"quota", "", "显示认证状态与每日请求上限", runQuota},
+		{"probe-rate-limit", "[--base-url URL | --live] [--start-rate 1] [--max-rate 20] [--step-duration 3s] [--drain] [--report 文件]", "逐步提高请求速率，找出触发限流的速率（默认使用模拟服务器）", runProbeRateLimit},
+		{"mock-server", "[--addr 127.0.0.1:8090] [--rate 5] [--burst 5]", "启动限流的模拟 TDX API", runMockServer},
+		{"send-test", "[--message 文字]", "发送 Telegram 测试消息", runSendTest},
+		{"config validate", "[配置文件]", "检查配置并列出所有问题", runConfigValidate},
+	}
+}
+
+// exitError 让命令指定进程退出码而不再输出错误信息
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func main() {
+	app.SetupLogger()
+	// 命令行工具只显示警告以上的日志，serve 会改回 info
+	logrus.SetLevel(logrus.WarnLevel)
+
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		usage(os.Stdout)
+		return
+	}
+
+	name, args := os.Args[1], os.Args[2:]
+	if (name == "stations" || name == "config") && len(args) > 0 {
+		name, args = name+" "+args[0], args[1:]
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(args)
+		if code, ok := err.(exitError); ok {
+			os.Exit(int(code))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "railctl %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "railctl: unknown command %q\n\n", name)
+	usage(os.Stderr)
+	os.Exit(2)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "用法: railctl <命令> [参数]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "命令:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.name, cmd.args, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "查询类命令都支持 --json 输出；参数需写在位置参数之前")
+}
+
+// newFlagSet 建立子命令的参数，所有子命令都有 --json
+func newFlagSet(name string) (*flag.FlagSet, *bool) {
+	fs := flag.NewFlagSet("railctl "+name, flag.ContinueOnError)
+	jsonOut := fs.Bool("json", false, "以 JSON 输出")
+	return fs, jsonOut
+}
+
+// operatorFlag 为子命令加上 --operator
+func operatorFlag(fs *flag.FlagSet) *string {
+	return fs.String("operator", string(tdx.OperatorTRA), "营运单位 TRA 或 THSR")
+}
+
+// loadConfig 读取 .env、配置文件与环境变量，不要求服务运行所需的必填项
+func loadConfig() (*config.Config, error) {
+	_ = godotenv.Load()
+
+	cfg, err := config.Read(config.FilePath())
+	if err != nil {
+		return nil, err
+	}
+	app.RegisterSecrets(cfg)
+	return cfg, nil
+}
+
+// newClient 读取配置并建立 TDX 客户端
+func newClient() (*config.Config, *tdx.Client, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := app.NewTDXClient(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, client, nil
+}
+
+// output 按 --json 输出 JSON，否则调用 text 输出表格
+func output(jsonOut bool, v interface{}, text func(w *tabwriter.Writer)) error {
+	if jsonOut {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	text(tw)
+	return tw.Flush()
+}
+
+// oneArg 取出唯一的位置参数，多个参数以空格连接（例如英文站名）
+func oneArg(fs *flag.FlagSet, what string) (string, error) {
+	if fs.NArg() == 0 {
+		return "", fmt.Errorf("missing %s", what)
+	}
+	return strings.Join(fs.Args(), " "), nil
+}
+
+func runServe(args []string) error {
+	logrus.SetLevel(logrus.InfoLevel)
+	return app.Serve()
+}
+
+func runConfigValidate(args []string) error {
+	if code := app.ValidateConfig(args); code != 0 {
+		return exitError(code)
+	}
+	return nil
+}
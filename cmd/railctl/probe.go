@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"tg-rail-shouting/internal/app"
+	"tg-rail-shouting/internal/tdx"
+	"tg-rail-shouting/internal/tdxmock"
+)
+
+// probeStep 是以同一个速率持续发出请求的结果
+type probeStep struct {
+	Rate     float64 `json:"rate"`     // 客户端限流器设置的每秒请求数
+	Achieved float64 `json:"achieved"` // 实际达到的每秒请求数
+	Drained  int     `json:"drained"`  // 测量前为清空突发额度而连续发出的请求数
+	Requests int     `json:"requests"`
+	OK       int     `json:"ok"`
+	Limited  int     `json:"limited"`
+	Errors   int     `json:"errors"`
+}
+
+type probeReport struct {
+	Target        string        `json:"target"`
+	StartedAt     time.Time     `json:"started_at"`
+	Drained       bool          `json:"drained"`         // 每一阶是否先清空服务器的突发额度
+	Burst         int           `json:"burst,omitempty"` // 第一次清空突发额度时连续成功的请求数
+	Steps         []probeStep   `json:"steps"`
+	TotalRequests int           `json:"total_requests"`
+	ThrottledAt   float64       `json:"throttled_at,omitempty"`   // 第一次收到 429 时的速率，0 表示没有触发
+	LastSafeRate  float64       `json:"last_safe_rate,omitempty"` // 没有收到 429 的最高速率
+	RetryAfter    time.Duration `json:"retry_after_ns,omitempty"` // 429 响应的 Retry-After
+	Recovered     *bool         `json:"recovered,omitempty"`      // 等待 Retry-After 后的请求是否成功
+	Stopped       string        `json:"stopped"`                  // 停止的原因
+}
+
+// probeResult 是单次请求的结果分类
+type probeResult int
+
+const (
+	probeOK probeResult = iota
+	probeLimited
+	probeError
+)
+
+// prober 发出测试请求并累计请求数
+type prober struct {
+	client      *tdx.Client
+	stationID   string
+	maxRequests int
+	report      *probeReport
+	limitErr    *tdx.APIError // 第一次收到的 429
+}
+
+// budget 返回是否还能再发出请求，不能时记录停止原因
+func (p *prober) budget() bool {
+	switch {
+	case p.report.TotalRequests >= p.maxRequests:
+		p.report.Stopped = "reached max-requests"
+		return false
+	case p.client.QuotaRemaining() == 0:
+		p.report.Stopped = "daily quota exhausted"
+		return false
+	}
+	return true
+}
+
+func (p *prober) request() probeResult {
+	_, err := p.client.GetTrainTimetable(p.stationID, 0)
+	p.report.TotalRequests++
+
+	var apiErr *tdx.APIError
+	switch {
+	case err == nil:
+		return probeOK
+	case errors.As(err, &apiErr) && apiErr.RateLimited():
+		if p.limitErr == nil {
+			p.limitErr = apiErr
+		}
+		return probeLimited
+	default:
+		return probeError
+	}
+}
+
+// drain 不经限流连续发出请求直到收到 429，让服务器的令牌桶见底，返回发出的请求数与其中成功的次数。
+// 否则服务器累积的突发额度会吸收超出的请求，较短的一阶测不出限流
+func (p *prober) drain() (sent, ok int, limited bool) {
+	setProbeRate(p.client, 0)
+	for p.budget() {
+		sent++
+		switch p.request() {
+		case probeOK:
+			ok++
+		case probeLimited:
+			return sent, ok, true
+		}
+	}
+	return sent, ok, false
+}
+
+// runProbeRateLimit 以客户端自己的限流器逐步提高请求速率，找出服务器开始返回 429 的速率。
+// 每一阶先清空服务器的突发额度（--live 时默认不清空），再按该速率持续请求一段时间。
+// 默认对内置的模拟服务器测试，不会消耗 TDX 的请求数
+func runProbeRateLimit(args []string) error {
+	fs, jsonOut := newFlagSet("probe-rate-limit")
+	baseURL := fs.String("base-url", "", "测试的 API 根路径，默认启动内置的模拟服务器")
+	live := fs.Bool("live", false, "对配置的 TDX API 测试（会消耗每日请求数）")
+	mockRate := fs.Float64("mock-rate", 5, "内置模拟服务器每秒接受的请求数")
+	mockBurst := fs.Int("mock-burst", 5, "内置模拟服务器可连续接受的请求数")
+	stationID := fs.String("station", "1180", "请求即时看板的台铁车站代码")
+	startRate := fs.Float64("start-rate", 1, "起始的每秒请求数")
+	maxRate := fs.Float64("max-rate", 20, "最高的每秒请求数")
+	stepRate := fs.Float64("step", 1, "每一阶提高的每秒请求数")
+	stepDuration := fs.Duration("step-duration", 3*time.Second, "每一阶持续请求的时间")
+	drain := fs.Bool("drain", true, "每一阶先不经限流连续请求到收到 429，清空服务器的突发额度（--live 时默认关闭）")
+	maxRequests := fs.Int("max-requests", 200, "最多发出的请求数")
+	reportPath := fs.String("report", "", "把测试报告以 JSON 写入此文件")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	// 对真实的 TDX API 不主动连续请求到被限流，除非明确指定 --drain
+	drainSet := false
+	fs.Visit(func(f *flag.Flag) {
+		drainSet = drainSet || f.Name == "drain"
+	})
+	if *live && !drainSet {
+		*drain = false
+	}
+	if *live && *drain {
+		fmt.Fprintln(os.Stderr, "⚠️ --drain 会不经限流连续请求 TDX API 直到收到 429")
+	}
+	if *startRate <= 0 || *stepRate <= 0 || *maxRate < *startRate || *stepDuration <= 0 {
+		return fmt.Errorf("invalid ramp: need 0 < start-rate <= max-rate, step > 0 and step-duration > 0")
+	}
+
+	var client *tdx.Client
+	report := probeReport{StartedAt: time.Now()}
+	switch {
+	case *live:
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if client, err = app.NewTDXClient(cfg); err != nil {
+			return err
+		}
+		report.Target = cfg.TDX.BaseURL
+		fmt.Fprintf(os.Stderr, "⚠️ 将对 %s 最多发出 %d 次请求，每次都会计入 TDX 的每日请求数\n", report.Target, *maxRequests)
+	case *baseURL != "":
+		client = tdx.NewClient("", "", *baseURL, "")
+		report.Target = *baseURL
+	default:
+		server := httptest.NewServer(tdxmock.New(*mockRate, *mockBurst))
+		defer server.Close()
+		client = tdx.NewClient("", "", server.URL, "")
+		report.Target = fmt.Sprintf("mock (%g req/s, burst %d)", *mockRate, *mockBurst)
+	}
+
+	p := &prober{client: client, stationID: *stationID, maxRequests: *maxRequests, report: &report}
+	report.Drained = *drain
+	report.Stopped = "reached max-rate"
+ramp:
+	for rate := *startRate; rate <= *maxRate; rate += *stepRate {
+		step := probeStep{Rate: rate}
+		if *drain {
+			sent, ok, limited := p.drain()
+			step.Drained = sent
+			if !limited {
+				if report.Stopped == "reached max-rate" {
+					report.Stopped = "no rate limit while draining burst"
+				}
+				break
+			}
+			if len(report.Steps) == 0 {
+				report.Burst = ok
+			}
+
+			// 令牌桶见底后等待两个间隔再开始，留一个令牌的余量吸收网络延迟的抖动；
+			// 速率超过服务器的限制时余量很快就会用完
+			time.Sleep(time.Duration(2 / rate * float64(time.Second)))
+		}
+		setProbeRate(client, rate)
+
+		start := time.Now()
+		for time.Since(start) < *stepDuration {
+			if !p.budget() {
+				break
+			}
+			step.Requests++
+			switch p.request() {
+			case probeOK:
+				step.OK++
+			case probeLimited:
+				step.Limited++
+			default:
+				step.Errors++
+			}
+		}
+
+		if elapsed := time.Since(start).Seconds(); elapsed > 0 && step.Requests > 1 {
+			step.Achieved = float64(step.Requests) / elapsed
+		}
+		report.Steps = append(report.Steps, step)
+
+		switch {
+		case step.Limited > 0:
+			report.ThrottledAt = rate
+			report.Stopped = "rate limited"
+			break ramp
+		case time.Since(start) < *stepDuration:
+			// 请求数用完，这一阶没有测完
+			break ramp
+		case step.OK > 0:
+			report.LastSafeRate = rate
+		}
+	}
+
+	// 等待 Retry-After 后再试一次，确认服务器是否按标头恢复
+	if report.ThrottledAt > 0 && report.TotalRequests < *maxRequests {
+		report.RetryAfter = p.limitErr.RetryAfter
+		wait := p.limitErr.RetryAfter
+		if wait <= 0 {
+			wait = time.Second
+		}
+		time.Sleep(wait)
+
+		setProbeRate(client, 0)
+		recovered := p.request() == probeOK
+		report.Recovered = &recovered
+	}
+
+	if *reportPath != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*reportPath, append(data, '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	return output(*jsonOut, report, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "目标:\t%s\n", report.Target)
+		fmt.Fprintf(w, "请求数:\t%d\n", report.TotalRequests)
+		if report.Burst > 0 {
+			fmt.Fprintf(w, "突发额度:\t%d\n", report.Burst)
+		}
+		fmt.Fprintf(w, "停止原因:\t%s\n\n", report.Stopped)
+
+		fmt.Fprintln(w, "速率\t实际\t清空\t请求\t成功\t429\t其他错误")
+		for _, step := range report.Steps {
+			fmt.Fprintf(w, "%g/s\t%.1f/s\t%d\t%d\t%d\t%d\t%d\n",
+				step.Rate, step.Achieved, step.Drained, step.Requests, step.OK, step.Limited, step.Errors)
+		}
+		fmt.Fprintln(w)
+
+		if report.ThrottledAt == 0 {
+			fmt.Fprintln(w, "结果:\t没有触发限流")
+			return
+		}
+		fmt.Fprintf(w, "结果:\t%g/s 时触发限流，安全速率 %g/s\n", report.ThrottledAt, report.LastSafeRate)
+		if !report.Drained {
+			fmt.Fprintln(w, "注意:\t没有清空突发额度，服务器的突发额度可能让触发限流的速率偏高")
+		}
+		if report.RetryAfter > 0 {
+			fmt.Fprintf(w, "Retry-After:\t%s\n", report.RetryAfter)
+		} else {
+			fmt.Fprintln(w, "Retry-After:\t无")
+		}
+		if report.Recovered != nil {
+			fmt.Fprintf(w, "等待后恢复:\t%t\n", *report.Recovered)
+		}
+	})
+}
+
+// setProbeRate 让两个等级都按 rate 发出请求且不允许突发，测试时不必关心当前是否已认证
+func setProbeRate(client *tdx.Client, rate float64) {
+	client.SetRateLimit(tdx.TierAnonymous, rate, 1)
+	client.SetRateLimit(tdx.TierAuthenticated, rate, 1)
+}
+
+// runMockServer 启动模拟的 TDX API，可配合 probe-rate-limit --base-url 使用
+func runMockServer(args []string) error {
+	fs, _ := newFlagSet("mock-server")
+	addr := fs.String("addr", "127.0.0.1:8090", "监听地址")
+	rate := fs.Float64("rate", 5, "每秒接受的请求数")
+	burst := fs.Int("burst", 5, "可连续接受的请求数")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Mock TDX API listening on http://%s (%g req/s, burst %d)\n", *addr, *rate, *burst)
+	return http.ListenAndServe(*addr, tdxmock.New(*rate, *burst))
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"tg-rail-shouting/internal/tdx"
+)
+
+func runBoard(args []string) error {
+	fs, jsonOut := newFlagSet("board")
+	operator := operatorFlag(fs)
+	direction := fs.Int("direction", -1, "行驶方向 0 或 1，默认两个方向都显示")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	query, err := oneArg(fs, "station")
+	if err != nil {
+		return err
+	}
+	op, err := tdx.ParseOperator(*operator)
+	if err != nil {
+		return err
+	}
+
+	_, client, err := newClient()
+	if err != nil {
+		return err
+	}
+	station, err := client.FindStation(op, query)
+	if err != nil {
+		return err
+	}
+
+	directions := []int{0, 1}
+	if *direction >= 0 {
+		directions = []int{*direction}
+	}
+	var trains []tdx.TrainInfo
+	for _, d := range directions {
+		board, err := client.GetLiveBoard(op, station.StationID, d)
+		if err != nil {
+			return err
+		}
+		trains = append(trains, board...)
+	}
+
+	return output(*jsonOut, trains, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "%s %s (%s)\n\n", op.DisplayName(), station.StationName.ZhTw, station.StationID)
+		fmt.Fprintln(w, "车次\t车种\t方向\t到达\t出发\t终点\t延误")
+		for _, t := range trains {
+			delay := "-"
+			if t.RunningStatus == 2 {
+				delay = "停驶"
+			} else if t.DelayMinutes > 0 {
+				delay = fmt.Sprintf("%d分", t.DelayMinutes)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				t.TrainNo, t.TrainType, t.Direction, t.ArrivalTime, t.DepartureTime, t.EndStation, delay)
+		}
+	})
+}
+
+func runRoute(args []string) error {
+	fs, jsonOut := newFlagSet("route")
+	operator := operatorFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	trainNo, err := oneArg(fs, "train number")
+	if err != nil {
+		return err
+	}
+	op, err := tdx.ParseOperator(*operator)
+	if err != nil {
+		return err
+	}
+
+	_, client, err := newClient()
+	if err != nil {
+		return err
+	}
+	route, err := client.GetRoute(op, trainNo)
+	if err != nil {
+		return err
+	}
+
+	return output(*jsonOut, route, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "%s %s次\n\n", op.DisplayName(), trainNo)
+		fmt.Fprintln(w, "#\t车站\t到达\t出发")
+		for _, st := range route {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", st.StopSequence, st.StationName, st.ArrivalTime, st.DepartureTime)
+		}
+	})
+}
+
+func runStationsSearch(args []string) error {
+	fs, jsonOut := newFlagSet("stations search")
+	operator := operatorFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	query, err := oneArg(fs, "search query")
+	if err != nil {
+		return err
+	}
+	op, err := tdx.ParseOperator(*operator)
+	if err != nil {
+		return err
+	}
+
+	_, client, err := newClient()
+	if err != nil {
+		return err
+	}
+	stations, err := client.SearchStations(op, query)
+	if err != nil {
+		return err
+	}
+
+	return output(*jsonOut, stations, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "代码\t名称\t英文名称")
+		for _, st := range stations {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", st.StationID, st.StationName.ZhTw, st.StationName.En)
+		}
+	})
+}
+
+type quotaStatus struct {
+	Authenticated bool      `json:"authenticated"`
+	TokenExpiry   time.Time `json:"token_expiry,omitempty"`
+	DailyQuota    int       `json:"daily_quota"` // 0 表示不限制
+	Error         string    `json:"error,omitempty"`
+}
+
+// runQuota 发出一次请求以确认认证信息，再显示认证状态与配置的每日请求上限。
+// TDX 不提供剩余次数的查询，服务运行时的剩余次数请看 /metrics 的 tdx_quota_remaining
+func runQuota(args []string) error {
+	fs, jsonOut := newFlagSet("quota")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	status := quotaStatus{DailyQuota: cfg.TDX.DailyQuota}
+	if _, err := client.GetStations(tdx.OperatorTRA); err != nil {
+		status.Error = err.Error()
+	}
+	status.Authenticated, status.TokenExpiry = client.TokenStatus()
+
+	return output(*jsonOut, status, func(w *tabwriter.Writer) {
+		tier := "免费（未认证）"
+		if status.Authenticated {
+			tier = fmt.Sprintf("已认证，token 有效至 %s", status.TokenExpiry.Format("2006-01-02 15:04:05"))
+		}
+		limit := "不限制"
+		if status.DailyQuota > 0 {
+			limit = fmt.Sprintf("%d 次", status.DailyQuota)
+		}
+		fmt.Fprintf(w, "认证状态:\t%s\n", tier)
+		fmt.Fprintf(w, "每日请求上限:\t%s\n", limit)
+		if status.Error != "" {
+			fmt.Fprintf(w, "测试请求失败:\t%s\n", status.Error)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"tg-rail-shouting/internal/app"
+)
+
+func runSendTest(args []string) error {
+	fs, jsonOut := newFlagSet("send-test")
+	message := fs.String("message", "", "消息内容，默认为测试消息")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.Telegram.BotToken == "" || cfg.Telegram.ChatID == "" {
+		return fmt.Errorf("TELEGRAM_BOT_TOKEN and TELEGRAM_CHAT_ID are required")
+	}
+
+	text := *message
+	if text == "" {
+		text = fmt.Sprintf("✅ railctl 测试消息\n时间: %s", time.Now().Format("2006-01-02 15:04:05"))
+	}
+
+	bot, err := app.NewTelegramBot(cfg)
+	if err != nil {
+		return err
+	}
+	if err := bot.SendMessage(text); err != nil {
+		return err
+	}
+
+	return output(*jsonOut, map[string]bool{"sent": true}, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "✅ 已发送")
+	})
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/monitor"
+	"tg-rail-shouting/internal/server"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// API 提供只读的 JSON 接口，与调度器共用同一个 tdx.Client（及其缓存与配额）
+type API struct {
+	tdxClient *tdx.Client
+	scheduler *monitor.Scheduler
+	apiKey    string
+}
+
+func New(tdxClient *tdx.Client, scheduler *monitor.Scheduler, apiKey string) *API {
+	return &API{
+		tdxClient: tdxClient,
+		scheduler: scheduler,
+		apiKey:    apiKey,
+	}
+}
+
+// Register 将接口挂载到 HTTP 服务器
+func (a *API) Register(srv *server.Server) {
+	srv.Handle("/stations/", a.authorize(http.HandlerFunc(a.handleStationBoard), false))
+	srv.Handle("/trains/", a.authorize(http.HandlerFunc(a.handleTrainRoute), false))
+	srv.Handle("/watches", a.authorize(http.HandlerFunc(a.handleWatches), false))
+	// 日历应用无法设置请求头，允许以 ?key= 传递密钥
+	srv.Handle("/watches/", a.authorize(http.HandlerFunc(a.handleWatchCalendar), true))
+	srv.Handle("/history", a.authorize(http.HandlerFunc(a.handleHistory), false))
+}
+
+// authorize 检查 X-API-Key 或 Authorization: Bearer 头，allowQueryKey 时也接受 ?key=
+func (a *API) authorize(next http.Handler, allowQueryKey bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if key == "" {
+			key = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if key == "" && allowQueryKey {
+			key = r.URL.Query().Get("key")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(key), []byte(a.apiKey)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleStationBoard 处理 GET /stations/{id}/board?direction=&operator=
+func (a *API) handleStationBoard(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/stations/"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "board" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	stationID := parts[0]
+
+	op, err := tdx.ParseOperator(r.URL.Query().Get("operator"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	direction := 1
+	if value := r.URL.Query().Get("direction"); value != "" {
+		direction, err = strconv.Atoi(value)
+		if err != nil || (direction != 0 && direction != 1) {
+			writeError(w, http.StatusBadRequest, "direction must be 0 or 1")
+			return
+		}
+	}
+
+	trains, err := a.tdxClient.GetLiveBoardContext(r.Context(), op, stationID, direction)
+	if err != nil {
+		logrus.WithError(err).WithField("station", stationID).Warn("API: failed to get live board")
+		writeUpstreamError(w)
+		return
+	}
+	if err := a.tdxClient.AnnotateAmenitiesContext(r.Context(), op, trains); err != nil {
+		logrus.WithError(err).Warn("API: failed to get train amenities")
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"operator":   op,
+		"station_id": stationID,
+		"direction":  direction,
+		"trains":     trains,
+	})
+}
+
+// handleTrainRoute 处理 GET /trains/{no}/route?operator=
+func (a *API) handleTrainRoute(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/trains/"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "route" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	trainNo := parts[0]
+
+	op, err := tdx.ParseOperator(r.URL.Query().Get("operator"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	route, err := a.tdxClient.GetRouteContext(r.Context(), op, trainNo)
+	if err != nil {
+		logrus.WithError(err).WithField("train", trainNo).Warn("API: failed to get train route")
+		writeUpstreamError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"operator": op,
+		"train_no": trainNo,
+		"stations": route,
+	})
+}
+
+// handleWatches 处理 GET /watches，附上每个配置档最新的检查结果
+func (a *API) handleWatches(w http.ResponseWriter, r *http.Request) {
+	type watchView struct {
+		Name                 string               `json:"name"`
+		Type                 string               `json:"type"`
+		Operator             tdx.Operator         `json:"operator"`
+		StationID            string               `json:"station_id"`
+		Direction            int                  `json:"direction"`
+		DestinationStationID string               `json:"destination_station_id,omitempty"`
+		Latest               *monitor.CheckResult `json:"latest,omitempty"`
+	}
+
+	var watches []watchView
+	for _, watch := range a.scheduler.Watches() {
+		view := watchView{
+			Name:                 watch.Name,
+			Type:                 watch.Type,
+			Operator:             watch.Operator,
+			StationID:            watch.StationID,
+			Direction:            watch.Direction,
+			DestinationStationID: watch.DestinationStationID,
+		}
+		if latest, ok := a.scheduler.LatestResult(watch.Name); ok {
+			view.Latest = &latest
+		}
+		watches = append(watches, view)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"watches":         watches,
+		"quota_remaining": a.tdxClient.QuotaRemaining(),
+	})
+}
+
+// handleHistory 处理 GET /history?limit=
+func (a *API) handleHistory(w http.ResponseWriter, r *http.Request) {
+	results := a.scheduler.History()
+	if value := r.URL.Query().Get("limit"); value != "" {
+		if limit, err := strconv.Atoi(value); err == nil && limit >= 0 && limit < len(results) {
+			results = results[:limit]
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"history": results,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.WithError(err).Warn("API: failed to write response")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeUpstreamError 回复上游请求失败。错误中可能有内部网址等细节，只记录在日志中，不返回给客户端
+func writeUpstreamError(w http.ResponseWriter) {
+	writeError(w, http.StatusBadGateway, "upstream request failed")
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/ical"
+	"tg-rail-shouting/internal/monitor"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// 日历默认涵盖未来 24 小时，最多 72 小时
+const (
+	defaultCalendarHours = 24
+	maxCalendarHours     = 72
+)
+
+// handleWatchCalendar 处理 GET /watches/{name}/calendar.ics?hours=，每次请求重新生成
+func (a *API) handleWatchCalendar(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/watches/"), "/")
+	parts := strings.Split(rest, "/")
+	if len(parts) != 2 || parts[1] != "calendar.ics" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	name, err := url.PathUnescape(parts[0])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid watch name")
+		return
+	}
+
+	var watch *config.WatchConfig
+	for _, candidate := range a.scheduler.Watches() {
+		if candidate.Name == name {
+			candidate := candidate
+			watch = &candidate
+			break
+		}
+	}
+	if watch == nil || watch.Type == config.WatchTypeSeats {
+		writeError(w, http.StatusNotFound, "watch not found")
+		return
+	}
+
+	hours := defaultCalendarHours
+	if value := r.URL.Query().Get("hours"); value != "" {
+		if hours, err = strconv.Atoi(value); err != nil || hours <= 0 || hours > maxCalendarHours {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("hours must be between 1 and %d", maxCalendarHours))
+			return
+		}
+	}
+
+	departures, err := a.scheduler.UpcomingDepartures(r.Context(), *watch, time.Now(), time.Duration(hours)*time.Hour)
+	if err != nil {
+		logrus.WithError(err).WithField("watch", watch.Name).Warn("API: failed to build calendar")
+		writeUpstreamError(w)
+		return
+	}
+
+	calendar := &ical.Calendar{
+		Name:     watch.Name + " 列车",
+		Location: tdx.Location,
+	}
+	live := liveTrains(a.scheduler, watch.Name)
+	for _, departure := range departures {
+		calendar.Events = append(calendar.Events, calendarEvent(*watch, departure, live))
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "watch.ics"))
+	if _, err := calendar.WriteTo(w); err != nil {
+		logrus.WithError(err).Warn("API: failed to write calendar")
+	}
+}
+
+// liveTrains 取出监控配置档最新一次即时看板的列车，用于附上延误信息
+func liveTrains(scheduler *monitor.Scheduler, watchName string) map[string]tdx.TrainInfo {
+	trains := make(map[string]tdx.TrainInfo)
+	if result, ok := scheduler.LatestResult(watchName); ok {
+		for _, train := range result.Trains {
+			trains[train.TrainNo] = train
+		}
+	}
+	return trains
+}
+
+func calendarEvent(watch config.WatchConfig, departure monitor.UpcomingDeparture, live map[string]tdx.TrainInfo) ical.Event {
+	train := departure.Train
+	fromStation := watch.Name
+	if len(train.Stations) > 0 {
+		fromStation = train.Stations[0].StationName
+	}
+
+	var description strings.Builder
+	description.WriteString(fmt.Sprintf("车次: %s (%s)\n", train.TrainNo, train.TrainType))
+	description.WriteString(fmt.Sprintf("出发: %s %s\n", departure.Departure.Format("15:04"), fromStation))
+	description.WriteString(fmt.Sprintf("到达: %s %s\n", departure.Arrival.Format("15:04"), departure.ArrivalStation))
+	if liveTrain, ok := live[train.TrainNo]; ok {
+		switch {
+		case liveTrain.RunningStatus == 2:
+			description.WriteString("即时: 取消")
+		case liveTrain.DelayMinutes > 0:
+			description.WriteString(fmt.Sprintf("即时: 晚%d分", liveTrain.DelayMinutes))
+		default:
+			description.WriteString("即时: 准点")
+		}
+		if liveTrain.Platform != "" {
+			description.WriteString(fmt.Sprintf("\n月台: %s", liveTrain.Platform))
+		}
+	}
+
+	return ical.Event{
+		UID:         fmt.Sprintf("%s-%s-%s@tg-rail-shouting", departure.Departure.Format("20060102"), train.TrainNo, watch.StationID),
+		Start:       departure.Departure,
+		End:         departure.Arrival,
+		Summary:     fmt.Sprintf("🚂 %s次 %s → %s", train.TrainNo, fromStation, departure.ArrivalStation),
+		Description: strings.TrimSuffix(description.String(), "\n"),
+		Location:    fromStation,
+	}
+}
@@ -0,0 +1,258 @@
+// Package app 组装并运行监控服务，供 main 与 railctl serve 共用
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/api"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/dashboard"
+	"tg-rail-shouting/internal/health"
+	"tg-rail-shouting/internal/metrics"
+	"tg-rail-shouting/internal/monitor"
+	"tg-rail-shouting/internal/redact"
+	"tg-rail-shouting/internal/server"
+	"tg-rail-shouting/internal/store"
+	"tg-rail-shouting/internal/tdx"
+	"tg-rail-shouting/internal/telegram"
+)
+
+// Serve 启动监控服务，收到 SIGINT/SIGTERM 后停止
+func Serve() error {
+	logrus.Info("Starting TG Rail Shouting service...")
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	RegisterSecrets(cfg)
+
+	tdxClient, err := NewTDXClient(cfg)
+	if err != nil {
+		return err
+	}
+	health.SetFailureThreshold(cfg.HTTP.HealthFailureThreshold)
+
+	// 在 access token 过期前于背景刷新
+	tokenCtx, stopRefreshing := context.WithCancel(context.Background())
+	defer stopRefreshing()
+	go tdxClient.RefreshTokens(tokenCtx)
+
+	tgBot, err := NewTelegramBot(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Send startup message with version
+	if err := tgBot.SendStartupMessage(); err != nil {
+		logrus.WithError(err).Warn("Failed to send startup message")
+	}
+
+	scheduler := monitor.NewScheduler(cfg, tdxClient, tgBot)
+
+	if cfg.Store.Path != "" {
+		observationStore, err := store.Open(cfg.Store.Path)
+		if err != nil {
+			// 延误记录不是必要功能，例如工作目录只读时仍继续运行
+			logrus.WithError(err).WithField("path", cfg.Store.Path).Warn("Failed to open delay store, delay statistics are disabled")
+		} else {
+			defer func() {
+				if err := observationStore.Close(); err != nil {
+					logrus.WithError(err).Warn("Failed to close delay store")
+				}
+			}()
+			scheduler.SetStore(observationStore)
+		}
+	}
+
+	if err := scheduler.SendTestMessage(); err != nil {
+		logrus.WithError(err).Warn("Failed to send test message")
+	}
+
+	if err := scheduler.Start(); err != nil {
+		return fmt.Errorf("failed to start scheduler: %w", err)
+	}
+
+	var httpServer *server.Server
+	if cfg.HTTP.Addr != "" {
+		httpServer = server.New(cfg.HTTP.Addr)
+		httpServer.Handle("/metrics", metrics.Handler())
+		httpServer.Handle("/healthz", health.Healthz(tdxClient))
+		httpServer.Handle("/readyz", health.Readyz(tdxClient))
+		if cfg.HTTP.Dashboard {
+			httpServer.Handle("/", dashboard.Handler(tdxClient, scheduler))
+		}
+		if cfg.HTTP.APIKey != "" {
+			api.New(tdxClient, scheduler, cfg.HTTP.APIKey).Register(httpServer)
+		}
+		httpServer.Start()
+	}
+
+	logrus.Info("Service started successfully")
+
+	// SIGHUP 或配置文件变动时重新加载配置
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	if path := config.FilePath(); cfg.WatchFile && path != "" {
+		go config.WatchFile(watchCtx, path, 5*time.Second, func() {
+			logrus.WithField("file", path).Info("Configuration file changed")
+			reload <- syscall.SIGHUP
+		})
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	for running := true; running; {
+		select {
+		case <-reload:
+			reloadConfig(tdxClient, scheduler)
+		case <-stop:
+			logrus.Info("Received shutdown signal")
+			running = false
+		}
+	}
+
+	scheduler.Stop()
+
+	if httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			logrus.WithError(err).Warn("Failed to shut down HTTP server")
+		}
+	}
+	logrus.Info("Service stopped")
+	return nil
+}
+
+// NewTDXClient 按配置建立 TDX 客户端
+func NewTDXClient(cfg *config.Config) (*tdx.Client, error) {
+	tdxClient := tdx.NewClient(
+		cfg.TDX.ClientID,
+		cfg.TDX.ClientSecret,
+		cfg.TDX.BaseURL,
+		cfg.TDX.AuthURL,
+	)
+	tdxClient.SetBaseURL(tdx.OperatorTHSR, cfg.TDX.THSRBaseURL)
+	tdxClient.SetDailyQuota(cfg.TDX.DailyQuota)
+	setRateLimits(tdxClient, cfg)
+	if err := tdxClient.SetTransport(cfg.Transport.Options()); err != nil {
+		return nil, fmt.Errorf("failed to configure TDX client: %w", err)
+	}
+	return tdxClient, nil
+}
+
+// NewTelegramBot 按配置建立 Telegram Bot
+func NewTelegramBot(cfg *config.Config) (*telegram.Bot, error) {
+	bot := telegram.NewBot(cfg.Telegram.BotToken, cfg.Telegram.ChatID)
+	if err := bot.SetTransport(cfg.Transport.Options()); err != nil {
+		return nil, fmt.Errorf("failed to configure Telegram bot: %w", err)
+	}
+	return bot, nil
+}
+
+// setRateLimits 按配置设置认证与未认证请求的限流
+func setRateLimits(tdxClient *tdx.Client, cfg *config.Config) {
+	tdxClient.SetRateLimit(tdx.TierAuthenticated, cfg.TDX.RateLimit, cfg.TDX.RateBurst)
+	tdxClient.SetRateLimit(tdx.TierAnonymous, cfg.TDX.AnonymousRateLimit, cfg.TDX.AnonymousRateBurst)
+}
+
+// reloadConfig 重新加载配置并套用到 TDX 客户端与调度器，配置有误时保留当前配置
+func reloadConfig(tdxClient *tdx.Client, scheduler *monitor.Scheduler) {
+	cfg, err := config.Load()
+	if err != nil {
+		logrus.WithError(err).Error("Failed to reload configuration, keeping the current one")
+		return
+	}
+	RegisterSecrets(cfg)
+
+	tdxClient.SetDailyQuota(cfg.TDX.DailyQuota)
+	setRateLimits(tdxClient, cfg)
+	health.SetFailureThreshold(cfg.HTTP.HealthFailureThreshold)
+
+	if err := scheduler.Reload(cfg); err != nil {
+		logrus.WithError(err).Error("Failed to apply reloaded configuration")
+		return
+	}
+	logrus.Info("Configuration reloaded")
+}
+
+// RegisterSecrets 让日志与聊天中的错误信息不会出现配置中的密钥
+func RegisterSecrets(cfg *config.Config) {
+	redact.Register(cfg.Telegram.BotToken, cfg.TDX.ClientSecret, cfg.HTTP.APIKey)
+	if proxy, err := url.Parse(cfg.Transport.Proxy); err == nil && proxy.User != nil {
+		if password, ok := proxy.User.Password(); ok {
+			redact.Register(password)
+		}
+	}
+}
+
+// Healthcheck 探测服务的 /healthz，供容器 HEALTHCHECK 使用，返回进程退出码。
+// 与服务以相同的方式读取 .env、配置文件与环境变量，以取得 HTTP 监听地址
+func Healthcheck(args []string) int {
+	url := ""
+	if len(args) > 0 {
+		url = args[0]
+	} else {
+		_ = godotenv.Load()
+		cfg, err := config.Read(config.FilePath())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if cfg.HTTP.Addr == "" {
+			fmt.Fprintln(os.Stderr, "HTTP server is disabled (http.addr / HTTP_ADDR is empty)")
+			return 1
+		}
+		url = health.ProbeURL(cfg.HTTP.Addr)
+	}
+
+	if err := health.Probe(url); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// ValidateConfig 检查配置并列出所有问题，返回进程退出码
+func ValidateConfig(args []string) int {
+	_ = godotenv.Load()
+
+	path := config.FilePath()
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	if _, err := config.LoadFile(path); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if path == "" {
+		path = "environment"
+	}
+	fmt.Printf("%s: configuration is valid\n", path)
+	return 0
+}
+
+// SetupLogger 设置日志格式，并移除日志中的密钥
+func SetupLogger() {
+	logrus.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp:   true,
+		TimestampFormat: "2006-01-02 15:04:05",
+	})
+	logrus.SetLevel(logrus.InfoLevel)
+	logrus.AddHook(redact.Hook{})
+}
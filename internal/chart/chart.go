@@ -0,0 +1,119 @@
+// Package chart 以纯 Go 绘制简单的 PNG 图表，用于 Telegram 报告
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	width        = 640
+	height       = 360
+	marginLeft   = 48
+	marginRight  = 16
+	marginTop    = 32
+	marginBottom = 36
+)
+
+var (
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	axisColor  = color.RGBA{0x44, 0x44, 0x44, 0xff}
+	gridColor  = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	barColor   = color.RGBA{0x2e, 0x86, 0xc1, 0xff}
+	textColor  = color.RGBA{0x22, 0x22, 0x22, 0xff}
+)
+
+// Bar 是长条图中的一根长条
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// BarChart 绘制长条图并编码为 PNG。basicfont 只支持 ASCII，标题与标签请使用英文或数字
+func BarChart(title string, bars []Bar) ([]byte, error) {
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no data to chart")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	maxValue := 0.0
+	for _, bar := range bars {
+		maxValue = math.Max(maxValue, bar.Value)
+	}
+	scale := niceCeiling(maxValue)
+
+	plotWidth := width - marginLeft - marginRight
+	plotHeight := height - marginTop - marginBottom
+	bottom := marginTop + plotHeight
+
+	// 横向格线与纵轴刻度
+	const ticks = 4
+	for i := 0; i <= ticks; i++ {
+		y := bottom - plotHeight*i/ticks
+		fillRect(img, marginLeft, y, marginLeft+plotWidth, y+1, gridColor)
+		drawText(img, fmt.Sprintf("%g", scale*float64(i)/ticks), 4, y+4)
+	}
+
+	slot := plotWidth / len(bars)
+	barWidth := slot * 2 / 3
+	for i, bar := range bars {
+		x := marginLeft + slot*i + (slot-barWidth)/2
+		barHeight := 0
+		if scale > 0 {
+			barHeight = int(float64(plotHeight) * math.Max(bar.Value, 0) / scale)
+		}
+		fillRect(img, x, bottom-barHeight, x+barWidth, bottom, barColor)
+
+		drawText(img, fmt.Sprintf("%.1f", bar.Value), x, bottom-barHeight-4)
+		drawText(img, bar.Label, x, bottom+16)
+	}
+
+	fillRect(img, marginLeft, marginTop, marginLeft+1, bottom, axisColor)
+	fillRect(img, marginLeft, bottom, marginLeft+plotWidth, bottom+1, axisColor)
+	drawText(img, title, marginLeft, marginTop-12)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// niceCeiling 把最大值向上取到 1、2、5 乘 10 的次方，让刻度是整齐的数字
+func niceCeiling(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, step := range []float64{1, 2, 5, 10} {
+		if v <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+func fillRect(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	draw.Draw(img, image.Rect(x0, y0, x1, y1), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+func drawText(img *image.RGBA, text string, x, y int) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{textColor},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
@@ -1,100 +1,323 @@
 package config
 
 import (
+	"fmt"
 	"os"
-	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/httpclient"
+	"tg-rail-shouting/internal/tdx"
 )
 
 type Config struct {
-	TDX TDXConfig
-	Telegram TelegramConfig  
-	Monitor MonitorConfig
-	Station StationConfig
+	TDX       TDXConfig       `yaml:"tdx"`
+	Telegram  TelegramConfig  `yaml:"telegram"`
+	Monitor   MonitorConfig   `yaml:"monitor"`
+	Station   StationConfig   `yaml:"station"`
+	Planner   PlannerConfig   `yaml:"planner"`
+	HTTP      HTTPConfig      `yaml:"http"`
+	Store     StoreConfig     `yaml:"store"`
+	Report    ReportConfig    `yaml:"report"`
+	Recommend RecommendConfig `yaml:"recommend"`
+	Transport TransportConfig `yaml:"transport"`
+	Watches   []WatchConfig   `yaml:"watches"`
+
+	WatchFile bool `yaml:"watch_file"` // 配置文件变动时自动重新加载
 }
 
 type TDXConfig struct {
-	ClientID     string
-	ClientSecret string
-	BaseURL      string
-	AuthURL      string
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	BaseURL      string `yaml:"base_url"`
+	THSRBaseURL  string `yaml:"thsr_base_url"`
+	AuthURL      string `yaml:"auth_url"`
+	DailyQuota   int    `yaml:"daily_quota"` // 每日请求上限，0 表示不限制，负数表示按是否认证自动决定
+	// 认证与未认证请求各自的每秒请求数上限（0 表示不限制）与可连续发出的请求数
+	RateLimit          float64 `yaml:"rate_limit"`
+	RateBurst          int     `yaml:"rate_burst"`
+	AnonymousRateLimit float64 `yaml:"anonymous_rate_limit"`
+	AnonymousRateBurst int     `yaml:"anonymous_rate_burst"`
 }
 
 type TelegramConfig struct {
-	BotToken string
-	ChatID   string
+	BotToken string `yaml:"bot_token"`
+	ChatID   string `yaml:"chat_id"`
 }
 
 type MonitorConfig struct {
-	StartHour        int
-	EndHour          int
-	IntervalMinutes  int
+	StartHour           int `yaml:"start_hour"`
+	EndHour             int `yaml:"end_hour"`
+	IntervalMinutes     int `yaml:"interval_minutes"`      // 平时的检查间隔
+	FastIntervalMinutes int `yaml:"fast_interval_minutes"` // 有列车即将出发或误点时的检查间隔
+	ApproachMinutes     int `yaml:"approach_minutes"`      // 列车在几分钟内出发时视为即将出发
 }
 
 type StationConfig struct {
-	ZhubeiStationID  string
-	TargetDirection  int
+	ZhubeiStationID   string `yaml:"zhubei_station_id"`
+	TargetDirection   int    `yaml:"target_direction"`
+	FareDestinationID string `yaml:"fare_destination_id"` // 可选：在列车信息中显示到该站的成人票价
 }
 
-func Load() (*Config, error) {
-	if err := godotenv.Load(); err != nil {
-		logrus.Warn("No .env file found, using environment variables")
+type HTTPConfig struct {
+	Addr                   string `yaml:"addr"`                     // 监听地址，空字符串表示不启动 HTTP 服务
+	HealthFailureThreshold int    `yaml:"health_failure_threshold"` // 连续失败几次后健康检查返回非 200
+	APIKey                 string `yaml:"api_key"`                  // REST API 的密钥，空字符串表示不启用 API
+	Dashboard              bool   `yaml:"dashboard"`                // 是否在 / 提供网页仪表板，页面不需要认证，默认关闭
+}
+
+type StoreConfig struct {
+	Path       string `yaml:"path"`        // 延误记录数据库的路径，空字符串表示不记录
+	StatsWeeks int    `yaml:"stats_weeks"` // /stats 默认统计的周数
+}
+
+type ReportConfig struct {
+	Cron  string `yaml:"cron"`  // 每周准点报告的 cron 表达式，空字符串表示不发送
+	Chart bool   `yaml:"chart"` // 是否附上各时段平均延误的 PNG 图表
+}
+
+type RecommendConfig struct {
+	ArriveBy   string `yaml:"arrive_by"`   // 希望到达目的站的时间 HH:MM，设置后在列车信息中标示推荐车次
+	Confidence int    `yaml:"confidence"`  // 按时到达的信心百分比
+	MinSamples int    `yaml:"min_samples"` // 至少需要几天的延误记录才会推荐该车次
+}
+
+// TransportConfig 是 TDX 与 Telegram 请求共用的 HTTP 传输设置
+type TransportConfig struct {
+	Proxy              string        `yaml:"proxy"`               // http://、https:// 或 socks5:// 代理，空字符串表示使用 HTTPS_PROXY 等环境变量
+	CAFile             string        `yaml:"ca_file"`             // 额外信任的 CA 证书（PEM）
+	ConnectTimeout     time.Duration `yaml:"connect_timeout"`     // 建立连接（含 TLS 握手）的超时
+	ReadTimeout        time.Duration `yaml:"read_timeout"`        // 每个请求等待并读取响应的超时
+	UserAgent          string        `yaml:"user_agent"`          // 空字符串表示使用默认的 User-Agent
+	DisableCompression bool          `yaml:"disable_compression"` // 不请求 gzip 压缩的响应
+}
+
+// Options 转换成 httpclient 的设置
+func (t TransportConfig) Options() httpclient.Options {
+	return httpclient.Options{
+		Proxy:              t.Proxy,
+		CAFile:             t.CAFile,
+		ConnectTimeout:     t.ConnectTimeout,
+		ReadTimeout:        t.ReadTimeout,
+		UserAgent:          t.UserAgent,
+		DisableCompression: t.DisableCompression,
+	}
+}
+
+type PlannerConfig struct {
+	MaxTransfers       int `yaml:"max_transfers"`
+	MinTransferMinutes int `yaml:"min_transfer_minutes"`
+}
+
+// 监控配置档的类型
+const (
+	WatchTypeBoard = "board" // 车站即时看板（默认）
+	WatchTypeSeats = "seats" // 高铁剩余座位，从售完变为有位时通知
+)
+
+// CronParser 解析监控配置档与每周报告的 cron 表达式：秒字段可省略，也支持 @every 1h 等写法
+var CronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// WatchConfig 描述一个监控配置档：监控哪个营运单位的哪个车站、哪个方向
+type WatchConfig struct {
+	Name                 string       `yaml:"name"`
+	Type                 string       `yaml:"type"`
+	Operator             tdx.Operator `yaml:"operator"`
+	StationID            string       `yaml:"station_id"`
+	Direction            int          `yaml:"direction"`
+	DestinationStationID string       `yaml:"destination_station_id"` // 可选：设置后会附上到该站的行程规划
+	Filter               TrainFilter  `yaml:"filter"`
+
+	// 可选：按这些 cron 表达式检查，不设置时使用自适应的检查间隔
+	Schedules []string      `yaml:"schedules"`
+	Jitter    time.Duration `yaml:"jitter"` // 每次排程检查前随机延迟的上限
+
+	// 以下仅用于 seats 类型：监控 StartDate 起 Days 天内的车次
+	StartDate string   `yaml:"start_date"` // YYYY-MM-DD，空字符串表示今天
+	Days      int      `yaml:"days"`
+	TrainNos  []string `yaml:"train_nos"` // 可选：只监控这些车次
+}
+
+// TrainFilter 限定监控配置档显示的列车，零值表示不过滤
+type TrainFilter struct {
+	RequireBike       bool     `yaml:"require_bike"`
+	RequireWheelchair bool     `yaml:"require_wheelchair"`
+	RequireDining     bool     `yaml:"require_dining"`
+	TrainTypes        []string `yaml:"train_types"` // 车种代码或名称，例如 "6"、"區間"、"普悠瑪"
+}
+
+// RequiresAmenities 判断是否按服务标记过滤，服务标记只有台铁的时刻表提供
+func (f TrainFilter) RequiresAmenities() bool {
+	return f.RequireBike || f.RequireWheelchair || f.RequireDining
+}
+
+// SeatDates 返回 seats 类型监控配置档需要查询的日期
+func (w WatchConfig) SeatDates(now time.Time) []time.Time {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if w.StartDate != "" {
+		if parsed, err := time.ParseInLocation("2006-01-02", w.StartDate, now.Location()); err == nil && parsed.After(start) {
+			start = parsed
+		}
+	}
+
+	days := w.Days
+	if days <= 0 {
+		days = 1
+	}
+
+	dates := make([]time.Time, 0, days)
+	for i := 0; i < days; i++ {
+		dates = append(dates, start.AddDate(0, 0, i))
 	}
+	return dates
+}
 
-	config := &Config{
+// defaults 返回所有配置项的默认值
+func defaults() *Config {
+	return &Config{
 		TDX: TDXConfig{
-			ClientID:     os.Getenv("TDX_CLIENT_ID"),
-			ClientSecret: os.Getenv("TDX_CLIENT_SECRET"),
-			BaseURL:      "https://tdx.transportdata.tw/api/basic/v3",
-			AuthURL:      "https://tdx.transportdata.tw/auth/realms/TDXConnect/protocol/openid-connect/token",
+			BaseURL:            "https://tdx.transportdata.tw/api/basic/v3",
+			THSRBaseURL:        "https://tdx.transportdata.tw/api/basic/v2",
+			AuthURL:            "https://tdx.transportdata.tw/auth/realms/TDXConnect/protocol/openid-connect/token",
+			DailyQuota:         -1,
+			RateLimit:          tdx.DefaultRateLimit,
+			RateBurst:          tdx.DefaultRateBurst,
+			AnonymousRateLimit: tdx.DefaultAnonymousRateLimit,
+			AnonymousRateBurst: tdx.DefaultAnonymousRateBurst,
 		},
-		Telegram: TelegramConfig{
-			BotToken: os.Getenv("TELEGRAM_BOT_TOKEN"),
-			ChatID:   os.Getenv("TELEGRAM_CHAT_ID"),
+		HTTP: HTTPConfig{
+			Addr:                   ":8080",
+			HealthFailureThreshold: 3,
 		},
 		Monitor: MonitorConfig{
-			StartHour:       getIntEnv("MONITOR_START_HOUR", 18),
-			EndHour:         getIntEnv("MONITOR_END_HOUR", 23),
-			IntervalMinutes: getIntEnv("MONITOR_INTERVAL_MINUTES", 30),
+			StartHour:           18,
+			EndHour:             23,
+			IntervalMinutes:     30,
+			FastIntervalMinutes: 3,
+			ApproachMinutes:     30,
 		},
 		Station: StationConfig{
-			ZhubeiStationID: os.Getenv("ZHUBEI_STATION_ID"),
-			TargetDirection: getIntEnv("TARGET_DIRECTION", 1),
+			TargetDirection: 1,
+		},
+		Planner: PlannerConfig{
+			MaxTransfers:       2,
+			MinTransferMinutes: 5,
+		},
+		Store: StoreConfig{
+			Path:       "data/observations.db",
+			StatsWeeks: 8,
+		},
+		Recommend: RecommendConfig{
+			Confidence: 90,
+			MinSamples: 3,
+		},
+		Report: ReportConfig{
+			Cron:  "0 21 * * 0",
+			Chart: true,
 		},
+		Transport: TransportConfig{
+			ConnectTimeout: httpclient.DefaultConnectTimeout,
+			ReadTimeout:    tdx.DefaultRequestTimeout,
+		},
+	}
+}
+
+// Load 读取 .env 与配置文件（CONFIG_FILE，未设置时使用存在的 config.yaml），
+// 以环境变量覆盖后校验，所有问题会合并成一个错误返回
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil {
+		logrus.Warn("No .env file found, using environment variables")
 	}
 
-	if err := validateConfig(config); err != nil {
+	config, err := LoadFile(FilePath())
+	if err != nil {
 		return nil, err
 	}
 
+	// TDX 认证信息可选（使用免费API）
+	if config.TDX.ClientID == "" || config.TDX.ClientSecret == "" {
+		logrus.Warn("TDX API credentials not provided, using free tier (50 requests/day limit)")
+	}
 	return config, nil
 }
 
-func getIntEnv(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
+// FilePath 返回要读取的配置文件路径，没有配置文件时返回空字符串
+func FilePath() string {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path
+	}
+	if _, err := os.Stat(defaultFile); err == nil {
+		return defaultFile
 	}
-	return defaultValue
+	return ""
 }
 
-func validateConfig(config *Config) error {
-	// TDX 认证信息可选（使用免费API）
-	if config.TDX.ClientID == "" || config.TDX.ClientSecret == "" {
-		logrus.Warn("TDX API credentials not provided, using free tier (50 requests/day limit)")
+// LoadFile 依序套用默认值、配置文件 path（可为空）与环境变量，并校验结果
+func LoadFile(path string) (*Config, error) {
+	config, errs, err := read(path)
+	if err != nil {
+		return nil, err
 	}
-	
-	if config.Telegram.BotToken == "" {
-		logrus.Fatal("TELEGRAM_BOT_TOKEN is required")
+
+	errs = append(errs, validateConfig(config)...)
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
 	}
-	if config.Telegram.ChatID == "" {
-		logrus.Fatal("TELEGRAM_CHAT_ID is required")
+	return config, nil
+}
+
+// Read 与 LoadFile 相同但不检查必填项，供只需要部分配置（例如 TDX）的命令行工具使用
+func Read(path string) (*Config, error) {
+	config, errs, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
 	}
-	if config.Station.ZhubeiStationID == "" {
-		logrus.Fatal("ZHUBEI_STATION_ID is required")
+	return config, nil
+}
+
+func read(path string) (*Config, []error, error) {
+	config := defaults()
+
+	if path != "" {
+		if err := readFile(path, config); err != nil {
+			return nil, nil, err
+		}
 	}
-	return nil
-}
\ No newline at end of file
+
+	var errs []error
+	errs = append(errs, applyEnv(config)...)
+	errs = append(errs, normalize(config)...)
+	return config, errs, nil
+}
+
+// normalize 补上监控配置档省略的字段，并根据是否认证决定默认的每日请求上限
+func normalize(config *Config) []error {
+	var errs []error
+
+	// 免费 API 默认每日 50 次，认证后的上限依会员等级而定，默认不限制
+	if config.TDX.DailyQuota < 0 {
+		config.TDX.DailyQuota = 0
+		if config.TDX.ClientID == "" || config.TDX.ClientSecret == "" {
+			config.TDX.DailyQuota = tdx.FreeTierDailyQuota
+		}
+	}
+
+	for i := range config.Watches {
+		watch := &config.Watches[i]
+		if watch.Type == "" {
+			watch.Type = WatchTypeBoard
+		}
+		op, err := tdx.ParseOperator(string(watch.Operator))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("watch %s: %w", watch.Name, err))
+			continue
+		}
+		watch.Operator = op
+	}
+	return errs
+}
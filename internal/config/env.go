@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"tg-rail-shouting/internal/tdx"
+)
+
+// applyEnv 以已设置的环境变量覆盖配置，返回无法解析的环境变量
+func applyEnv(config *Config) []error {
+	env := &envReader{}
+
+	env.secret(&config.TDX.ClientID, "TDX_CLIENT_ID")
+	env.secret(&config.TDX.ClientSecret, "TDX_CLIENT_SECRET")
+	env.int(&config.TDX.DailyQuota, "TDX_DAILY_QUOTA")
+	env.float(&config.TDX.RateLimit, "TDX_RATE_LIMIT")
+	env.int(&config.TDX.RateBurst, "TDX_RATE_BURST")
+	env.float(&config.TDX.AnonymousRateLimit, "TDX_ANONYMOUS_RATE_LIMIT")
+	env.int(&config.TDX.AnonymousRateBurst, "TDX_ANONYMOUS_RATE_BURST")
+
+	env.secret(&config.Telegram.BotToken, "TELEGRAM_BOT_TOKEN")
+	env.secret(&config.Telegram.ChatID, "TELEGRAM_CHAT_ID")
+
+	env.int(&config.Monitor.StartHour, "MONITOR_START_HOUR")
+	env.int(&config.Monitor.EndHour, "MONITOR_END_HOUR")
+	env.int(&config.Monitor.IntervalMinutes, "MONITOR_INTERVAL_MINUTES")
+	env.int(&config.Monitor.FastIntervalMinutes, "MONITOR_FAST_INTERVAL_MINUTES")
+	env.int(&config.Monitor.ApproachMinutes, "MONITOR_APPROACH_MINUTES")
+
+	env.string(&config.Station.ZhubeiStationID, "ZHUBEI_STATION_ID")
+	env.int(&config.Station.TargetDirection, "TARGET_DIRECTION")
+	env.string(&config.Station.FareDestinationID, "FARE_DESTINATION_STATION_ID")
+
+	env.int(&config.Planner.MaxTransfers, "PLANNER_MAX_TRANSFERS")
+	env.int(&config.Planner.MinTransferMinutes, "PLANNER_MIN_TRANSFER_MINUTES")
+
+	// 这几项设置为空字符串表示停用，因此只要设置了就覆盖
+	env.optionalString(&config.HTTP.Addr, "HTTP_ADDR")
+	env.int(&config.HTTP.HealthFailureThreshold, "HEALTH_FAILURE_THRESHOLD")
+	env.secret(&config.HTTP.APIKey, "API_KEY")
+	env.bool(&config.HTTP.Dashboard, "DASHBOARD_ENABLED")
+
+	env.optionalString(&config.Store.Path, "STORE_PATH")
+	env.int(&config.Store.StatsWeeks, "STATS_WEEKS")
+
+	env.optionalString(&config.Report.Cron, "WEEKLY_REPORT_CRON")
+	env.bool(&config.Report.Chart, "WEEKLY_REPORT_CHART")
+
+	env.string(&config.Recommend.ArriveBy, "RECOMMEND_ARRIVE_BY")
+	env.int(&config.Recommend.Confidence, "RECOMMEND_CONFIDENCE")
+	env.int(&config.Recommend.MinSamples, "RECOMMEND_MIN_SAMPLES")
+
+	// 代理地址可能包含账号密码
+	env.secret(&config.Transport.Proxy, "TRANSPORT_PROXY")
+	env.string(&config.Transport.CAFile, "TRANSPORT_CA_FILE")
+	env.duration(&config.Transport.ConnectTimeout, "TRANSPORT_CONNECT_TIMEOUT")
+	env.duration(&config.Transport.ReadTimeout, "TRANSPORT_READ_TIMEOUT")
+	env.string(&config.Transport.UserAgent, "TRANSPORT_USER_AGENT")
+	env.bool(&config.Transport.DisableCompression, "TRANSPORT_DISABLE_COMPRESSION")
+
+	env.bool(&config.WatchFile, "CONFIG_WATCH")
+
+	// 配置文件没有定义监控配置档时，由环境变量组成默认的监控配置档
+	if len(config.Watches) == 0 {
+		watch := WatchConfig{
+			Name:                 "竹北",
+			Type:                 WatchTypeBoard,
+			Operator:             tdx.Operator(os.Getenv("WATCH_OPERATOR")),
+			StationID:            config.Station.ZhubeiStationID,
+			Direction:            config.Station.TargetDirection,
+			DestinationStationID: os.Getenv("DESTINATION_STATION_ID"),
+			Schedules:            getScheduleEnv("WATCH_SCHEDULES"),
+			Filter: TrainFilter{
+				TrainTypes: getListEnv("WATCH_TRAIN_TYPES"),
+			},
+		}
+		env.bool(&watch.Filter.RequireBike, "WATCH_REQUIRE_BIKE")
+		env.bool(&watch.Filter.RequireWheelchair, "WATCH_REQUIRE_WHEELCHAIR")
+		env.bool(&watch.Filter.RequireDining, "WATCH_REQUIRE_DINING")
+		env.duration(&watch.Jitter, "WATCH_JITTER")
+		config.Watches = append(config.Watches, watch)
+	}
+
+	// 可选：高铁剩余座位监控
+	if origin := os.Getenv("SEAT_WATCH_ORIGIN_ID"); origin != "" {
+		watch := WatchConfig{
+			Name:                 "高鐵座位",
+			Type:                 WatchTypeSeats,
+			Operator:             tdx.OperatorTHSR,
+			StationID:            origin,
+			DestinationStationID: os.Getenv("SEAT_WATCH_DESTINATION_ID"),
+			StartDate:            os.Getenv("SEAT_WATCH_START_DATE"),
+			Days:                 1,
+			TrainNos:             getListEnv("SEAT_WATCH_TRAINS"),
+			Schedules:            getScheduleEnv("SEAT_WATCH_SCHEDULES"),
+		}
+		env.int(&watch.Days, "SEAT_WATCH_DAYS")
+		env.duration(&watch.Jitter, "SEAT_WATCH_JITTER")
+		config.Watches = append(config.Watches, watch)
+	}
+
+	return env.errs
+}
+
+// envReader 读取环境变量并记录无法解析的值，未设置或为空的变量不会覆盖原值
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) string(target *string, key string) {
+	if value := os.Getenv(key); value != "" {
+		*target = value
+	}
+}
+
+// secret 读取密钥，也可以用 KEY_FILE 指定包含密钥的文件（Docker/Kubernetes secrets）
+func (e *envReader) secret(target *string, key string) {
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		e.string(target, key)
+		return
+	}
+	if os.Getenv(key) != "" {
+		e.errs = append(e.errs, fmt.Errorf("%s and %s_FILE are both set", key, key))
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s_FILE: %w", key, err))
+		return
+	}
+	*target = strings.TrimSpace(string(data))
+}
+
+// optionalString 只要设置了环境变量（包括空字符串）就覆盖
+func (e *envReader) optionalString(target *string, key string) {
+	if value, ok := os.LookupEnv(key); ok {
+		*target = value
+	}
+}
+
+func (e *envReader) int(target *int, key string) {
+	if value := os.Getenv(key); value != "" {
+		intValue, err := strconv.Atoi(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid integer %q", key, value))
+			return
+		}
+		*target = intValue
+	}
+}
+
+func (e *envReader) float(target *float64, key string) {
+	if value := os.Getenv(key); value != "" {
+		floatValue, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid number %q", key, value))
+			return
+		}
+		*target = floatValue
+	}
+}
+
+func (e *envReader) bool(target *bool, key string) {
+	if value := os.Getenv(key); value != "" {
+		boolValue, err := strconv.ParseBool(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid boolean %q", key, value))
+			return
+		}
+		*target = boolValue
+	}
+}
+
+func (e *envReader) duration(target *time.Duration, key string) {
+	if value := os.Getenv(key); value != "" {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("%s: invalid duration %q", key, value))
+			return
+		}
+		*target = duration
+	}
+}
+
+// getListEnv 读取以逗号分隔的列表
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getScheduleEnv 读取以分号分隔的 cron 表达式列表（cron 表达式本身可能包含逗号）
+func getScheduleEnv(key string) []string {
+	var specs []string
+	for _, spec := range strings.Split(os.Getenv(key), ";") {
+		if spec = strings.TrimSpace(spec); spec != "" {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
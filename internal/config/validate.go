@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"tg-rail-shouting/internal/httpclient"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// ValidationError 汇总配置中的所有问题
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("invalid configuration (%d problems):", len(e.Errors)))
+	for _, err := range e.Errors {
+		lines = append(lines, "  - "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
+
+// validateConfig 检查配置，返回发现的所有问题
+func validateConfig(config *Config) []error {
+	var errs []error
+	addf := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if config.Telegram.BotToken == "" {
+		addf("telegram.bot_token (TELEGRAM_BOT_TOKEN) is required")
+	}
+	if config.Telegram.ChatID == "" {
+		addf("telegram.chat_id (TELEGRAM_CHAT_ID) is required")
+	}
+
+	if config.TDX.RateLimit < 0 || config.TDX.RateBurst < 0 || config.TDX.AnonymousRateLimit < 0 || config.TDX.AnonymousRateBurst < 0 {
+		addf("tdx rate limits and bursts must not be negative")
+	}
+
+	if config.Transport.Proxy != "" {
+		if _, err := httpclient.ParseProxy(config.Transport.Proxy); err != nil {
+			addf("transport.proxy: %v", err)
+		}
+	}
+	if config.Transport.CAFile != "" {
+		if _, err := os.Stat(config.Transport.CAFile); err != nil {
+			addf("transport.ca_file: %v", err)
+		}
+	}
+	if config.Transport.ConnectTimeout < 0 || config.Transport.ReadTimeout < 0 {
+		addf("transport timeouts must not be negative")
+	}
+
+	if config.Monitor.StartHour < 0 || config.Monitor.StartHour > 23 || config.Monitor.EndHour < 0 || config.Monitor.EndHour > 23 {
+		addf("monitor.start_hour and monitor.end_hour must be between 0 and 23")
+	}
+	if config.Monitor.IntervalMinutes <= 0 || config.Monitor.FastIntervalMinutes <= 0 {
+		addf("monitor intervals must be positive")
+	}
+	if config.Monitor.ApproachMinutes < 0 {
+		addf("monitor.approach_minutes must not be negative")
+	}
+	if config.HTTP.HealthFailureThreshold <= 0 {
+		addf("http.health_failure_threshold must be positive")
+	}
+	if config.Store.StatsWeeks <= 0 {
+		addf("store.stats_weeks must be positive")
+	}
+	if config.Planner.MaxTransfers < 0 || config.Planner.MinTransferMinutes < 0 {
+		addf("planner settings must not be negative")
+	}
+
+	if config.Recommend.ArriveBy != "" {
+		if _, err := time.Parse("15:04", config.Recommend.ArriveBy); err != nil {
+			addf("recommend.arrive_by %q: expected HH:MM", config.Recommend.ArriveBy)
+		}
+	}
+	if config.Recommend.Confidence <= 0 || config.Recommend.Confidence > 100 {
+		addf("recommend.confidence must be between 1 and 100")
+	}
+	if config.Report.Cron != "" {
+		if _, err := CronParser.Parse(config.Report.Cron); err != nil {
+			addf("report.cron %q: %v", config.Report.Cron, err)
+		}
+	}
+
+	if len(config.Watches) == 0 {
+		addf("at least one watch is required")
+	}
+	names := make(map[string]bool)
+	for i, watch := range config.Watches {
+		errs = append(errs, validateWatch(i, watch, names)...)
+	}
+
+	return errs
+}
+
+func validateWatch(index int, watch WatchConfig, names map[string]bool) []error {
+	var errs []error
+	label := watch.Name
+	if label == "" {
+		label = fmt.Sprintf("#%d", index+1)
+		errs = append(errs, fmt.Errorf("watch %s: name is required", label))
+	} else if names[watch.Name] {
+		errs = append(errs, fmt.Errorf("watch %s: duplicate name", label))
+	}
+	names[watch.Name] = true
+
+	addf := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("watch %s: "+format, append([]interface{}{label}, args...)...))
+	}
+
+	if watch.StationID == "" {
+		if watch.Type == WatchTypeSeats {
+			addf("origin station is required")
+		} else {
+			addf("station_id is required (ZHUBEI_STATION_ID for the default watch)")
+		}
+	}
+	for _, spec := range watch.Schedules {
+		if _, err := CronParser.Parse(spec); err != nil {
+			addf("invalid schedule %q: %v", spec, err)
+		}
+	}
+	if watch.Jitter < 0 {
+		addf("jitter must not be negative")
+	}
+
+	switch watch.Type {
+	case WatchTypeBoard:
+		if watch.Direction != 0 && watch.Direction != 1 {
+			addf("direction must be 0 or 1")
+		}
+		if watch.Operator != tdx.OperatorTRA && watch.Filter.RequiresAmenities() {
+			addf("require_bike, require_wheelchair and require_dining are only supported for TRA")
+		}
+	case WatchTypeSeats:
+		if watch.Operator != tdx.OperatorTHSR {
+			addf("seat watches only support THSR")
+		}
+		if watch.DestinationStationID == "" {
+			addf("destination station is required for seat watches")
+		}
+		if watch.StartDate != "" {
+			if _, err := time.Parse("2006-01-02", watch.StartDate); err != nil {
+				addf("invalid start date %q", watch.StartDate)
+			}
+		}
+	default:
+		addf("unknown type %q", watch.Type)
+	}
+
+	return errs
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tg-rail-shouting/internal/tdx"
+)
+
+// validConfig 返回通过校验的最小配置
+func validConfig() *Config {
+	config := defaults()
+	config.Telegram = TelegramConfig{BotToken: "token", ChatID: "123"}
+	config.Watches = []WatchConfig{{
+		Name:      "竹北",
+		Type:      WatchTypeBoard,
+		Operator:  tdx.OperatorTRA,
+		StationID: "1180",
+		Direction: 1,
+	}}
+	return config
+}
+
+func TestValidateConfig(t *testing.T) {
+	if errs := validateConfig(validConfig()); len(errs) != 0 {
+		t.Fatalf("valid config reported errors: %v", errs)
+	}
+
+	seatWatch := WatchConfig{
+		Name:                 "高铁",
+		Type:                 WatchTypeSeats,
+		Operator:             tdx.OperatorTHSR,
+		StationID:            "1030",
+		DestinationStationID: "1070",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   []string
+	}{
+		{"missing telegram", func(c *Config) { c.Telegram = TelegramConfig{} }, []string{"telegram.bot_token", "telegram.chat_id"}},
+		{"negative rate limit", func(c *Config) { c.TDX.AnonymousRateBurst = -1 }, []string{"rate limits"}},
+		{"bad proxy", func(c *Config) { c.Transport.Proxy = "ftp://proxy:21" }, []string{"transport.proxy"}},
+		{"missing CA file", func(c *Config) { c.Transport.CAFile = "/nonexistent/ca.pem" }, []string{"transport.ca_file"}},
+		{"negative timeout", func(c *Config) { c.Transport.ReadTimeout = -time.Second }, []string{"transport timeouts"}},
+		{"hour out of range", func(c *Config) { c.Monitor.EndHour = 24 }, []string{"monitor.start_hour"}},
+		{"zero interval", func(c *Config) { c.Monitor.FastIntervalMinutes = 0 }, []string{"monitor intervals"}},
+		{"zero health threshold", func(c *Config) { c.HTTP.HealthFailureThreshold = 0 }, []string{"health_failure_threshold"}},
+		{"zero stats weeks", func(c *Config) { c.Store.StatsWeeks = 0 }, []string{"store.stats_weeks"}},
+		{"negative planner", func(c *Config) { c.Planner.MaxTransfers = -1 }, []string{"planner"}},
+		{"bad arrive_by", func(c *Config) { c.Recommend.ArriveBy = "7pm" }, []string{"recommend.arrive_by"}},
+		{"confidence out of range", func(c *Config) { c.Recommend.Confidence = 101 }, []string{"recommend.confidence"}},
+		{"bad report cron", func(c *Config) { c.Report.Cron = "every sunday" }, []string{"report.cron"}},
+		{"no watches", func(c *Config) { c.Watches = nil }, []string{"at least one watch"}},
+		{"unnamed watch", func(c *Config) { c.Watches[0].Name = "" }, []string{"watch #1: name is required"}},
+		{"duplicate watch", func(c *Config) { c.Watches = append(c.Watches, c.Watches[0]) }, []string{"watch 竹北: duplicate name"}},
+		{"missing station", func(c *Config) { c.Watches[0].StationID = "" }, []string{"station_id is required"}},
+		{"bad schedule", func(c *Config) { c.Watches[0].Schedules = []string{"* * *"} }, []string{"invalid schedule"}},
+		{"negative jitter", func(c *Config) { c.Watches[0].Jitter = -time.Second }, []string{"jitter"}},
+		{"bad direction", func(c *Config) { c.Watches[0].Direction = 2 }, []string{"direction must be 0 or 1"}},
+		{
+			"THSR board watch with amenity filter",
+			func(c *Config) {
+				c.Watches[0].Operator = tdx.OperatorTHSR
+				c.Watches[0].Filter.RequireBike = true
+			},
+			[]string{"only supported for TRA"},
+		},
+		{"unknown type", func(c *Config) { c.Watches[0].Type = "fares" }, []string{`unknown type "fares"`}},
+		{"valid seat watch", func(c *Config) { c.Watches = append(c.Watches, seatWatch) }, nil},
+		{
+			"TRA seat watch without destination",
+			func(c *Config) {
+				watch := seatWatch
+				watch.Operator = tdx.OperatorTRA
+				watch.DestinationStationID = ""
+				watch.StartDate = "2024/01/01"
+				c.Watches = []WatchConfig{watch}
+			},
+			[]string{"seat watches only support THSR", "destination station is required", "invalid start date"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig()
+			tt.modify(config)
+			errs := validateConfig(config)
+
+			if len(errs) != len(tt.want) {
+				t.Fatalf("got %d errors %v, want %d", len(errs), errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidationErrorListsAllProblems(t *testing.T) {
+	config := validConfig()
+	config.Telegram.ChatID = ""
+	config.Monitor.IntervalMinutes = 0
+
+	err := &ValidationError{Errors: validateConfig(config)}
+	message := err.Error()
+	for _, want := range []string{"(2 problems)", "telegram.chat_id", "monitor intervals"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message %q does not contain %q", message, want)
+		}
+	}
+}
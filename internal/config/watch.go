@@ -0,0 +1,35 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchFile 定期检查配置文件的修改时间，文件变动时调用 onChange
+func WatchFile(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	modTime := func() time.Time {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+
+	last := modTime()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if current := modTime(); !current.IsZero() && !current.Equal(last) {
+			last = current
+			onChange()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package dashboard
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/monitor"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// 页面自动刷新间隔（秒）
+const refreshSeconds = 60
+
+// 每个监控配置档最多显示的列车数
+const maxTrains = 8
+
+//go:embed dashboard.html
+var pageTemplate string
+
+var page = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"delayText": delayText,
+}).Parse(pageTemplate))
+
+type watchView struct {
+	Name       string
+	Type       string
+	Station    string
+	Direction  string
+	LastUpdate string
+	Error      string
+	Trains     []tdx.TrainInfo
+	HasResult  bool
+}
+
+type pageData struct {
+	Refresh        int
+	Now            string
+	QuotaRemaining int
+	Watches        []watchView
+}
+
+// Handler 返回仪表板页面，显示每个监控配置档最新一次检查的列车
+func Handler(tdxClient *tdx.Client, scheduler *monitor.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		data := pageData{
+			Refresh:        refreshSeconds,
+			Now:            time.Now().Format("2006-01-02 15:04:05"),
+			QuotaRemaining: tdxClient.QuotaRemaining(),
+		}
+
+		for _, watch := range scheduler.Watches() {
+			view := watchView{
+				Name:      watch.Name,
+				Type:      watch.Type,
+				Station:   watch.Operator.DisplayName() + " " + watch.StationID,
+				Direction: directionText(watch),
+			}
+			if result, ok := scheduler.LatestResult(watch.Name); ok {
+				view.HasResult = true
+				view.LastUpdate = result.Time.Format("15:04:05")
+				view.Error = result.Error
+				view.Trains = result.Trains
+				if len(view.Trains) > maxTrains {
+					view.Trains = view.Trains[:maxTrains]
+				}
+			}
+			data.Watches = append(data.Watches, view)
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := page.Execute(w, data); err != nil {
+			logrus.WithError(err).Warn("Failed to render dashboard")
+		}
+	}
+}
+
+func directionText(watch config.WatchConfig) string {
+	if watch.Type == config.WatchTypeSeats {
+		return "→ " + watch.DestinationStationID
+	}
+	if watch.Direction == 1 {
+		return "北上"
+	}
+	return "南下"
+}
+
+func delayText(train tdx.TrainInfo) string {
+	switch {
+	case train.RunningStatus == 2:
+		return "取消"
+	case train.DelayMinutes > 0:
+		return fmt.Sprintf("晚%d分", train.DelayMinutes)
+	default:
+		return "准点"
+	}
+}
@@ -0,0 +1,189 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 连续失败达到此次数后视为不健康
+const DefaultFailureThreshold = 3
+
+// tracker 记录服务各部分最近的运行状况
+type tracker struct {
+	mu                  sync.Mutex
+	failureThreshold    int
+	consecutiveFailures int
+	lastCheck           time.Time
+	lastCheckFailed     bool
+	lastTDXSuccess      time.Time
+	lastTelegramSuccess time.Time
+	schedulerRunning    bool
+}
+
+var state = &tracker{failureThreshold: DefaultFailureThreshold}
+
+// SetFailureThreshold 设置连续失败几次后 /healthz 返回非 200
+func SetFailureThreshold(n int) {
+	if n <= 0 {
+		n = DefaultFailureThreshold
+	}
+	state.mu.Lock()
+	state.failureThreshold = n
+	state.mu.Unlock()
+}
+
+// RecordCheck 记录一次监控检查的结果
+func RecordCheck(err error) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.lastCheck = time.Now()
+	if err != nil {
+		state.consecutiveFailures++
+		state.lastCheckFailed = true
+		return
+	}
+	state.consecutiveFailures = 0
+	state.lastCheckFailed = false
+}
+
+// RecordTDXFetch 记录一次 TDX 请求的结果
+func RecordTDXFetch(err error) {
+	if err != nil {
+		return
+	}
+	state.mu.Lock()
+	state.lastTDXSuccess = time.Now()
+	state.mu.Unlock()
+}
+
+// RecordTelegramSend 记录一次 Telegram 发送的结果
+func RecordTelegramSend(err error) {
+	if err != nil {
+		return
+	}
+	state.mu.Lock()
+	state.lastTelegramSuccess = time.Now()
+	state.mu.Unlock()
+}
+
+func SetSchedulerRunning(running bool) {
+	state.mu.Lock()
+	state.schedulerRunning = running
+	state.mu.Unlock()
+}
+
+// TokenSource 提供 TDX access token 的状态
+type TokenSource interface {
+	TokenStatus() (authenticated bool, expiresAt time.Time)
+}
+
+type TokenStatus struct {
+	Authenticated bool       `json:"authenticated"`
+	Valid         bool       `json:"valid"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+}
+
+type Status struct {
+	Healthy             bool        `json:"healthy"`
+	Ready               bool        `json:"ready"`
+	Scheduler           string      `json:"scheduler"`
+	ConsecutiveFailures int         `json:"consecutive_failures"`
+	FailureThreshold    int         `json:"failure_threshold"`
+	LastCheck           *time.Time  `json:"last_check,omitempty"`
+	LastCheckFailed     bool        `json:"last_check_failed"` // 健康检查不需要认证，只显示是否失败，错误内容见日志
+	LastTDXSuccess      *time.Time  `json:"last_tdx_success,omitempty"`
+	LastTelegramSuccess *time.Time  `json:"last_telegram_success,omitempty"`
+	Token               TokenStatus `json:"token"`
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// Current 返回当前的健康状态
+func Current(tokens TokenSource) Status {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	status := Status{
+		Healthy:             state.consecutiveFailures < state.failureThreshold,
+		Scheduler:           "stopped",
+		ConsecutiveFailures: state.consecutiveFailures,
+		FailureThreshold:    state.failureThreshold,
+		LastCheck:           timePtr(state.lastCheck),
+		LastCheckFailed:     state.lastCheckFailed,
+		LastTDXSuccess:      timePtr(state.lastTDXSuccess),
+		LastTelegramSuccess: timePtr(state.lastTelegramSuccess),
+	}
+	if state.schedulerRunning {
+		status.Scheduler = "running"
+	}
+
+	if tokens != nil {
+		authenticated, expiresAt := tokens.TokenStatus()
+		status.Token = TokenStatus{
+			Authenticated: authenticated,
+			// 未认证时使用免费 API，不需要 token
+			Valid:     !authenticated || time.Now().Before(expiresAt),
+			ExpiresAt: timePtr(expiresAt),
+		}
+	}
+
+	status.Ready = status.Healthy && state.schedulerRunning && status.Token.Valid
+	return status
+}
+
+// Healthz 存活检查：最近连续 N 次检查失败时返回 503
+func Healthz(tokens TokenSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := Current(tokens)
+		writeStatus(w, status, status.Healthy)
+	}
+}
+
+// Readyz 就绪检查：调度器未运行、token 失效或最近连续 N 次检查失败时返回 503
+func Readyz(tokens TokenSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := Current(tokens)
+		writeStatus(w, status, status.Ready)
+	}
+}
+
+func writeStatus(w http.ResponseWriter, status Status, ok bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
+
+// Probe 请求健康检查地址，非 200 时返回错误，供容器 HEALTHCHECK 使用
+func Probe(url string) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed with status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ProbeURL 由监听地址组出本机的健康检查 URL，例如 ":8080" -> http://127.0.0.1:8080/healthz
+func ProbeURL(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		addr = "127.0.0.1" + addr
+	}
+	return fmt.Sprintf("http://%s/healthz", addr)
+}
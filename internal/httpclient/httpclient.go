@@ -0,0 +1,116 @@
+// Package httpclient 按配置建立 TDX 与 Telegram 客户端共用的 HTTP 传输设置
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// 默认值
+const (
+	DefaultUserAgent      = "tg-rail-shouting (+https://github.com/123hi123/tg-rail-shouting)"
+	DefaultConnectTimeout = 10 * time.Second
+)
+
+// Options 描述对外 HTTP 请求的传输设置，零值表示全部使用默认值
+type Options struct {
+	Proxy              string        // http://、https:// 或 socks5:// 代理，空字符串表示使用 HTTPS_PROXY 等环境变量
+	CAFile             string        // 除系统 CA 外额外信任的 CA 证书（PEM）
+	ConnectTimeout     time.Duration // 建立连接（含 TLS 握手）的超时，0 表示 DefaultConnectTimeout
+	ReadTimeout        time.Duration // 每个请求等待并读取响应的超时，0 表示使用各客户端的默认值
+	UserAgent          string        // 空字符串表示 DefaultUserAgent
+	DisableCompression bool          // 不请求 gzip 压缩的响应
+}
+
+// ParseProxy 解析并检查代理地址
+func ParseProxy(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL has no host: %s", u.Redacted())
+	}
+	return u, nil
+}
+
+// Transport 按 opts 建立 http.Transport
+func Transport(opts Options) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	connectTimeout := opts.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+	transport.DialContext = (&net.Dialer{
+		Timeout:   connectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.DisableCompression = opts.DisableCompression
+
+	if opts.Proxy != "" {
+		proxyURL, err := ParseProxy(opts.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", opts.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return transport, nil
+}
+
+// New 按 opts 建立 resty 客户端
+func New(opts Options) (*resty.Client, error) {
+	transport, err := Transport(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	client := resty.NewWithClient(&http.Client{Transport: transport})
+	client.SetHeader("User-Agent", userAgent)
+	return client, nil
+}
+
+// Default 以默认设置建立 resty 客户端
+func Default() *resty.Client {
+	client, err := New(Options{})
+	if err != nil {
+		// 默认设置不会读取文件或解析地址，不会失败
+		panic(err)
+	}
+	return client
+}
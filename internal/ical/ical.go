@@ -0,0 +1,124 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Event 是日历中的一个 VEVENT
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+}
+
+// Calendar 是一个 iCalendar (RFC 5545) 文件，事件时间以 Location 时区输出
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	Events   []Event
+}
+
+const timeLayout = "20060102T150405"
+
+// WriteTo 输出 .ics 内容，行尾使用 CRLF，长行按 75 字节折行
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	lw := &lineWriter{w: w}
+	tzid := c.Location.String()
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//tg-rail-shouting//train calendar//ZH")
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	lw.line("X-WR-CALNAME:" + escape(c.Name))
+	lw.line("X-WR-TIMEZONE:" + tzid)
+	c.writeTimezone(lw, tzid)
+
+	stamp := time.Now().UTC().Format(timeLayout) + "Z"
+	for _, event := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + escape(event.UID))
+		lw.line("DTSTAMP:" + stamp)
+		lw.line(fmt.Sprintf("DTSTART;TZID=%s:%s", tzid, event.Start.In(c.Location).Format(timeLayout)))
+		lw.line(fmt.Sprintf("DTEND;TZID=%s:%s", tzid, event.End.In(c.Location).Format(timeLayout)))
+		lw.line("SUMMARY:" + escape(event.Summary))
+		if event.Description != "" {
+			lw.line("DESCRIPTION:" + escape(event.Description))
+		}
+		if event.Location != "" {
+			lw.line("LOCATION:" + escape(event.Location))
+		}
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+	return lw.n, lw.err
+}
+
+// writeTimezone 输出不含夏令时的 VTIMEZONE（台湾不实施夏令时）
+func (c *Calendar) writeTimezone(lw *lineWriter, tzid string) {
+	_, offset := time.Now().In(c.Location).Zone()
+	name, _ := time.Now().In(c.Location).Zone()
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	utcOffset := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+
+	lw.line("BEGIN:VTIMEZONE")
+	lw.line("TZID:" + tzid)
+	lw.line("BEGIN:STANDARD")
+	lw.line("DTSTART:19700101T000000")
+	lw.line("TZOFFSETFROM:" + utcOffset)
+	lw.line("TZOFFSETTO:" + utcOffset)
+	lw.line("TZNAME:" + name)
+	lw.line("END:STANDARD")
+	lw.line("END:VTIMEZONE")
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+type lineWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// line 写入一行内容，超过 75 字节时折行（不拆开 UTF-8 字符）
+func (lw *lineWriter) line(content string) {
+	if lw.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range content {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	n, err := io.WriteString(lw.w, b.String())
+	lw.n += int64(n)
+	lw.err = err
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tg_rail"
+
+var (
+	// TDXRequests 按接口与 HTTP 状态码统计 TDX 请求数，网络错误的 status 为 "error"
+	TDXRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tdx",
+		Name:      "requests_total",
+		Help:      "TDX API requests by endpoint and HTTP status.",
+	}, []string{"endpoint", "status"})
+
+	TDXRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "tdx",
+		Name:      "request_duration_seconds",
+		Help:      "TDX API request latency by endpoint.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	TDXTokenRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tdx",
+		Name:      "token_refreshes_total",
+		Help:      "TDX access token refreshes by result.",
+	}, []string{"result"})
+
+	TDXCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tdx",
+		Name:      "cache_lookups_total",
+		Help:      "TDX response cache lookups by cache and result (hit/miss).",
+	}, []string{"cache", "result"})
+
+	TDXRateLimitWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "tdx",
+		Name:      "rate_limit_wait_seconds",
+		Help:      "Time TDX requests waited for the client-side rate limiter by tier.",
+		Buckets:   []float64{0, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"tier"})
+
+	TDXQuotaRemaining = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tdx",
+		Name:      "quota_remaining",
+		Help:      "Remaining TDX requests for today, -1 when unlimited.",
+	})
+
+	TelegramMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "telegram",
+		Name:      "messages_total",
+		Help:      "Telegram messages sent by result (success/failure).",
+	}, []string{"result"})
+
+	SchedulerTickDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "tick_duration_seconds",
+		Help:      "Duration of a scheduler check across all watches.",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60},
+	})
+
+	SchedulerPollInterval = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "poll_interval_seconds",
+		Help:      "Delay until the next adaptive check.",
+	})
+
+	TrainsFound = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "trains_found",
+		Help:      "Trains found by the last check of each watch.",
+	}, []string{"watch"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		TDXRequests,
+		TDXRequestDuration,
+		TDXTokenRefreshes,
+		TDXCacheLookups,
+		TDXRateLimitWait,
+		TDXQuotaRemaining,
+		TelegramMessages,
+		SchedulerTickDuration,
+		SchedulerPollInterval,
+		TrainsFound,
+	)
+}
+
+// Result 将错误转换成 success/failure 标签值
+func Result(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
+
+// Handler 返回 Prometheus 抓取用的 /metrics 处理函数
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/metrics"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// pollLoop 取代固定间隔的 cron：每次检查后，按是否有列车即将出发或误点、
+// 以及当天剩余的 TDX 请求数决定下一次检查的时间
+func (s *Scheduler) pollLoop() {
+	defer close(s.loopDone)
+
+	// 即使目前没有自适应的配置档也继续循环，重新加载配置后可能新增
+	cost := s.measureCost(s.runInitialCheck)
+
+	for {
+		interval := s.nextInterval(time.Now(), cost)
+		metrics.SchedulerPollInterval.Set(interval.Seconds())
+		logrus.WithField("interval", interval.Round(time.Second)).Debug("Next check scheduled")
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if c := s.measureCost(s.checkTrains); c > 0 {
+			cost = c
+		}
+	}
+}
+
+// measureCost 执行一次检查并返回其消耗的 TDX 请求数（不限制配额时无法得知，返回 0）
+func (s *Scheduler) measureCost(check func()) int {
+	before := s.tdxClient.QuotaRemaining()
+	check()
+	after := s.tdxClient.QuotaRemaining()
+	if before < 0 || after < 0 || before < after {
+		return 0
+	}
+	return before - after
+}
+
+// nextInterval 计算下一次检查前的等待时间。cost 是一次检查大约消耗的请求数
+func (s *Scheduler) nextInterval(now time.Time, cost int) time.Duration {
+	interval := time.Duration(s.cfg().Monitor.IntervalMinutes) * time.Minute
+	if s.trainsImminent(now) {
+		interval = time.Duration(s.cfg().Monitor.FastIntervalMinutes) * time.Minute
+	}
+
+	remaining := s.tdxClient.QuotaRemaining()
+	if remaining < 0 {
+		return interval
+	}
+	if cost <= 0 {
+		cost = 1
+	}
+
+	// 将剩余的请求数平均分配到重置之前，用完时等到重置
+	untilReset := s.tdxClient.QuotaResetsAt().Sub(now)
+	checks := remaining / cost
+	if checks == 0 {
+		return untilReset
+	}
+	if budget := untilReset / time.Duration(checks); budget > interval {
+		return budget
+	}
+	return interval
+}
+
+// trainsImminent 判断最新的检查结果中是否有列车即将出发或正在误点
+func (s *Scheduler) trainsImminent(now time.Time) bool {
+	approach := time.Duration(s.cfg().Monitor.ApproachMinutes) * time.Minute
+	now = now.In(tdx.Location)
+
+	for _, watch := range s.adaptiveWatches() {
+		if watch.Type == config.WatchTypeSeats {
+			continue
+		}
+		result, ok := s.LatestResult(watch.Name)
+		if !ok {
+			continue
+		}
+
+		for _, train := range result.Trains {
+			if train.DelayMinutes > 0 || train.RunningStatus == 1 {
+				return true
+			}
+
+			clock := train.DepartureTime
+			if clock == "" {
+				clock = train.ArrivalTime
+			}
+			departure, ok := tdx.StopTimeOn(now, clock)
+			if !ok {
+				continue
+			}
+			// 跨午夜的车次
+			if now.Sub(departure) > 12*time.Hour {
+				departure = departure.AddDate(0, 0, 1)
+			}
+			if until := departure.Sub(now); until >= 0 && until <= approach {
+				return true
+			}
+		}
+	}
+	return false
+}
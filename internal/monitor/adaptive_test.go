@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/tdx"
+)
+
+func newTestScheduler(quota int, trains ...tdx.TrainInfo) *Scheduler {
+	watch := config.WatchConfig{Name: "test", Type: config.WatchTypeBoard, Operator: tdx.OperatorTRA, StationID: "1180"}
+	cfg := &config.Config{
+		Monitor: config.MonitorConfig{IntervalMinutes: 30, FastIntervalMinutes: 3, ApproachMinutes: 30},
+		Watches: []config.WatchConfig{watch},
+	}
+
+	client := tdx.NewClient("", "", "http://127.0.0.1:0", "")
+	client.SetDailyQuota(quota)
+
+	s := NewScheduler(cfg, client, nil)
+	if trains != nil {
+		s.history.record(watch, trains, nil)
+	}
+	return s
+}
+
+// departingIn 返回 d 之后出发的列车
+func departingIn(now time.Time, d time.Duration) tdx.TrainInfo {
+	clock := now.In(tdx.Location).Add(d).Format("15:04")
+	return tdx.TrainInfo{TrainNo: "1", ArrivalTime: clock, DepartureTime: clock}
+}
+
+func TestNextInterval(t *testing.T) {
+	now := time.Now().In(tdx.Location)
+	untilReset := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tdx.Location).AddDate(0, 0, 1).Sub(now)
+
+	tests := []struct {
+		name   string
+		quota  int
+		cost   int
+		trains []tdx.TrainInfo
+		want   time.Duration
+	}{
+		{
+			name:  "unlimited quota, nothing imminent",
+			quota: 0,
+			cost:  1,
+			want:  30 * time.Minute,
+		},
+		{
+			name:   "unlimited quota, train departing soon",
+			quota:  0,
+			cost:   1,
+			trains: []tdx.TrainInfo{departingIn(now, 10*time.Minute)},
+			want:   3 * time.Minute,
+		},
+		{
+			name:   "delayed train is imminent",
+			quota:  0,
+			cost:   1,
+			trains: []tdx.TrainInfo{{TrainNo: "1", DepartureTime: now.In(tdx.Location).Add(-3 * time.Hour).Format("15:04"), DelayMinutes: 5}},
+			want:   3 * time.Minute,
+		},
+		{
+			name:   "train beyond approach window",
+			quota:  0,
+			cost:   1,
+			trains: []tdx.TrainInfo{departingIn(now, 2*time.Hour)},
+			want:   30 * time.Minute,
+		},
+		{
+			name:  "plenty of quota keeps interval",
+			quota: 100000,
+			cost:  1,
+			want:  30 * time.Minute,
+		},
+		{
+			name:  "scarce quota spreads checks until reset",
+			quota: 2,
+			cost:  1,
+			want:  max(untilReset/2, 30*time.Minute),
+		},
+		{
+			name:  "check costs more than remaining quota",
+			quota: 2,
+			cost:  5,
+			want:  untilReset,
+		},
+		{
+			name:  "zero cost treated as one request",
+			quota: 1,
+			cost:  0,
+			want:  max(untilReset, 30*time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(tt.quota, tt.trains...)
+			// QuotaResetsAt 以 time.Now 计算，允许些微差距
+			if got := s.nextInterval(now, tt.cost); got-tt.want > time.Second || tt.want-got > time.Second {
+				t.Errorf("nextInterval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
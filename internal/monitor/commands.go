@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"tg-rail-shouting/internal/tdx"
+)
+
+// registerCommands 注册 Telegram 聊天指令
+func (s *Scheduler) registerCommands() {
+	s.tgBot.HandleCommand("fare", s.handleFare)
+	s.tgBot.HandleCommand("plan", s.handlePlan)
+	s.tgBot.HandleCommand("stats", s.handleStats)
+	s.tgBot.HandleCommand("recommend", s.handleRecommend)
+}
+
+// splitOperator 取出指令开头可选的营运单位参数，例如 /plan THSR 新竹 臺北
+func splitOperator(args []string) (tdx.Operator, []string) {
+	if len(args) > 0 {
+		if op, err := tdx.ParseOperator(args[0]); err == nil {
+			return op, args[1:]
+		}
+	}
+	return tdx.OperatorTRA, args
+}
+
+// handleFare 处理 /fare <起站> <讫站>，站名或车站代码均可
+func (s *Scheduler) handleFare(ctx context.Context, args []string) (string, error) {
+	if len(args) != 2 {
+		return "用法: /fare &lt;起站&gt; &lt;讫站&gt;\n例如: /fare 竹北 富岡", nil
+	}
+
+	origin, err := s.tdxClient.FindStationContext(ctx, tdx.OperatorTRA, args[0])
+	if err != nil {
+		return "", err
+	}
+	destination, err := s.tdxClient.FindStationContext(ctx, tdx.OperatorTRA, args[1])
+	if err != nil {
+		return "", err
+	}
+
+	fares, err := s.tdxClient.GetODFareContext(ctx, origin.StationID, destination.StationID)
+	if err != nil {
+		return "", err
+	}
+
+	prices := fares.AdultFares()
+	if len(prices) == 0 {
+		return fmt.Sprintf("💰 %s → %s\n\n暂无票价信息", origin.StationName.ZhTw, destination.StationName.ZhTw), nil
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("💰 <b>%s → %s 成人全票</b>\n\n", origin.StationName.ZhTw, destination.StationName.ZhTw))
+	for _, trainType := range tdx.SortedFareTrainTypes(prices) {
+		message.WriteString(fmt.Sprintf("🚂 %s: NT$%d\n", tdx.FareTrainTypeName(trainType), prices[trainType]))
+	}
+
+	return message.String(), nil
+}
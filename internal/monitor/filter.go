@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"strings"
+
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// filterTrains 按监控配置档的条件过滤列车
+func filterTrains(trains []tdx.TrainInfo, filter config.TrainFilter) []tdx.TrainInfo {
+	var matched []tdx.TrainInfo
+	for _, train := range trains {
+		if matchesFilter(train, filter) {
+			matched = append(matched, train)
+		}
+	}
+	return matched
+}
+
+func matchesFilter(train tdx.TrainInfo, filter config.TrainFilter) bool {
+	if filter.RequireBike && !train.Bike {
+		return false
+	}
+	if filter.RequireWheelchair && !train.Wheelchair {
+		return false
+	}
+	if filter.RequireDining && !train.Dining {
+		return false
+	}
+	if len(filter.TrainTypes) == 0 {
+		return true
+	}
+
+	names := trainTypeNames(train.TrainType)
+	for _, trainType := range filter.TrainTypes {
+		if train.TrainTypeCode == trainType {
+			return true
+		}
+		for _, name := range names {
+			if name == trainType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// trainTypeNames 返回车种名称可供比对的写法：完整名称，以及括号前后的名称，
+// 例如 "自強(普悠瑪)" 可以用 "自強(普悠瑪)"、"自強" 或 "普悠瑪" 指定，但 "區間" 不会匹配 "區間快"
+func trainTypeNames(name string) []string {
+	names := []string{name}
+	base, rest, ok := strings.Cut(name, "(")
+	if !ok {
+		return names
+	}
+	if inner, _, ok := strings.Cut(rest, ")"); ok && inner != "" {
+		names = append(names, inner)
+	}
+	return append(names, strings.TrimSpace(base))
+}
@@ -0,0 +1,39 @@
+package monitor
+
+import (
+	"testing"
+
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/tdx"
+)
+
+func TestMatchesFilterTrainTypes(t *testing.T) {
+	local := tdx.TrainInfo{TrainType: "區間", TrainTypeCode: "6"}
+	localExpress := tdx.TrainInfo{TrainType: "區間快", TrainTypeCode: "10"}
+	puyuma := tdx.TrainInfo{TrainType: "自強(普悠瑪)", TrainTypeCode: "2"}
+
+	tests := []struct {
+		name  string
+		train tdx.TrainInfo
+		types []string
+		want  bool
+	}{
+		{"no filter", localExpress, nil, true},
+		{"exact name", local, []string{"區間"}, true},
+		{"prefix does not match", localExpress, []string{"區間"}, false},
+		{"code", localExpress, []string{"10"}, true},
+		{"code is exact", local, []string{"1"}, false},
+		{"full name with qualifier", puyuma, []string{"自強(普悠瑪)"}, true},
+		{"qualifier", puyuma, []string{"普悠瑪"}, true},
+		{"base name", puyuma, []string{"自強"}, true},
+		{"any of several", localExpress, []string{"自強", "區間快"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesFilter(tt.train, config.TrainFilter{TrainTypes: tt.types}); got != tt.want {
+				t.Errorf("matchesFilter(%q, %v) = %v, want %v", tt.train.TrainType, tt.types, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// 保留最近的检查记录数
+const historySize = 100
+
+// CheckResult 是对一个监控配置档的一次检查结果
+type CheckResult struct {
+	Time      time.Time       `json:"time"`
+	Watch     string          `json:"watch"`
+	Operator  tdx.Operator    `json:"operator"`
+	StationID string          `json:"station_id"`
+	Direction int             `json:"direction"`
+	Trains    []tdx.TrainInfo `json:"trains"`
+	Error     string          `json:"error,omitempty"` // 概括的错误，不含网址等细节，完整的错误只记录在日志中
+}
+
+// history 保存最近的检查结果，以及每个监控配置档最新的一次结果
+type history struct {
+	mu      sync.RWMutex
+	results []CheckResult
+	latest  map[string]CheckResult
+}
+
+func newHistory() *history {
+	return &history{latest: make(map[string]CheckResult)}
+}
+
+func (h *history) record(watch config.WatchConfig, trains []tdx.TrainInfo, err error) {
+	result := CheckResult{
+		Time:      time.Now(),
+		Watch:     watch.Name,
+		Operator:  watch.Operator,
+		StationID: watch.StationID,
+		Direction: watch.Direction,
+		Trains:    trains,
+	}
+	if err != nil {
+		result.Error = publicError(err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.results = append(h.results, result)
+	if len(h.results) > historySize {
+		h.results = h.results[len(h.results)-historySize:]
+	}
+	h.latest[watch.Name] = result
+}
+
+// History 返回最近的检查结果，最新的在前
+func (s *Scheduler) History() []CheckResult {
+	s.history.mu.RLock()
+	defer s.history.mu.RUnlock()
+
+	results := make([]CheckResult, 0, len(s.history.results))
+	for i := len(s.history.results) - 1; i >= 0; i-- {
+		results = append(results, s.history.results[i])
+	}
+	return results
+}
+
+// LatestResult 返回监控配置档最新的一次检查结果
+func (s *Scheduler) LatestResult(watchName string) (CheckResult, bool) {
+	s.history.mu.RLock()
+	defer s.history.mu.RUnlock()
+
+	result, ok := s.history.latest[watchName]
+	return result, ok
+}
+
+// Watches 返回当前的监控配置档
+func (s *Scheduler) Watches() []config.WatchConfig {
+	return s.cfg().Watches
+}
+
+// publicError 把检查失败的原因概括成可以在 API 与仪表板显示的文字
+func publicError(err error) string {
+	var apiErr *tdx.APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.RateLimited():
+		return "TDX rate limit exceeded"
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("TDX returned HTTP %d", apiErr.StatusCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "TDX request timed out"
+	default:
+		return "TDX request failed"
+	}
+}
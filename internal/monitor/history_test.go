@@ -0,0 +1,36 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/tdx"
+)
+
+func TestHistoryRecordHidesErrorDetail(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&tdx.APIError{StatusCode: 429}, "TDX rate limit exceeded"},
+		{fmt.Errorf("failed to get station live board: %w", &tdx.APIError{StatusCode: 500, Body: "internal detail"}), "TDX returned HTTP 500"},
+		{fmt.Errorf(`Get "http://10.0.0.5/api": %w`, context.DeadlineExceeded), "TDX request timed out"},
+		{errors.New(`Get "http://10.0.0.5/api?token=x": connection refused`), "TDX request failed"},
+	}
+
+	h := newHistory()
+	for _, tt := range tests {
+		h.record(config.WatchConfig{Name: "test"}, nil, tt.err)
+		got := h.latest["test"].Error
+		if got != tt.want {
+			t.Errorf("error %q recorded as %q, want %q", tt.err, got, tt.want)
+		}
+		if strings.Contains(got, "10.0.0.5") || strings.Contains(got, "detail") {
+			t.Errorf("recorded error leaks detail: %q", got)
+		}
+	}
+}
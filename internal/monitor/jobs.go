@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/metrics"
+)
+
+// addWatchJobs 为设置了 cron 表达式的监控配置档注册排程
+func (s *Scheduler) addWatchJobs() error {
+	for _, watch := range s.cfg().Watches {
+		if err := s.addJobsFor(watch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addJobsFor 为监控配置档的每个 cron 表达式注册一个具名的工作
+func (s *Scheduler) addJobsFor(watch config.WatchConfig) error {
+	for i, spec := range watch.Schedules {
+		name := fmt.Sprintf("%s#%d", watch.Name, i+1)
+		id, err := s.cron.AddFunc(spec, s.watchJob(name, watch))
+		if err != nil {
+			return fmt.Errorf("failed to add job %s (%s): %w", name, spec, err)
+		}
+		s.jobs[watch.Name] = append(s.jobs[watch.Name], id)
+		logrus.WithFields(logrus.Fields{"job": name, "schedule": spec}).Info("Scheduled watch job")
+	}
+	return nil
+}
+
+// removeJobsFor 移除监控配置档的排程工作，正在执行的工作会继续完成
+func (s *Scheduler) removeJobsFor(watchName string) {
+	for _, id := range s.jobs[watchName] {
+		s.cron.Remove(id)
+	}
+	delete(s.jobs, watchName)
+}
+
+// addReportJob 注册每周准点报告的排程
+func (s *Scheduler) addReportJob() error {
+	spec := s.cfg().Report.Cron
+	if spec == "" {
+		return nil
+	}
+	id, err := s.cron.AddFunc(spec, s.sendWeeklyReport)
+	if err != nil {
+		return fmt.Errorf("failed to add weekly report job: %w", err)
+	}
+	s.reportJob = id
+	return nil
+}
+
+// watchJob 返回检查单一监控配置档的排程工作，执行前先随机延迟 watch.Jitter 以内的时间
+func (s *Scheduler) watchJob(name string, watch config.WatchConfig) func() {
+	return func() {
+		if watch.Jitter > 0 {
+			delay := time.Duration(rand.Int63n(int64(watch.Jitter)))
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+
+		if !s.shouldMonitor() {
+			return
+		}
+
+		logrus.WithField("job", name).Info("Running scheduled watch job")
+		start := time.Now()
+		s.checkWatch(watch, false)
+		metrics.SchedulerTickDuration.Observe(time.Since(start).Seconds())
+	}
+}
+
+// adaptiveWatches 返回没有设置 cron 表达式、由自适应间隔检查的监控配置档
+func (s *Scheduler) adaptiveWatches() []config.WatchConfig {
+	var watches []config.WatchConfig
+	for _, watch := range s.cfg().Watches {
+		if len(watch.Schedules) == 0 {
+			watches = append(watches, watch)
+		}
+	}
+	return watches
+}
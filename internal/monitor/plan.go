@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/planner"
+	"tg-rail-shouting/internal/tdx"
+	"tg-rail-shouting/internal/telegram"
+)
+
+// 每次规划最多列出的行程数
+const maxItineraries = 3
+
+// planJourney 用当天的每日时刻表规划 originID 到 destinationID 的行程
+func (s *Scheduler) planJourney(ctx context.Context, op tdx.Operator, originID, destinationID string, departAfter time.Time) ([]planner.Itinerary, error) {
+	// 时刻表使用台湾时间，与容器的时区无关
+	departAfter = departAfter.In(tdx.Location)
+	timetables, err := s.tdxClient.GetDailyTimetableContext(ctx, op, departAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	p := planner.New(departAfter, timetables)
+	return p.Plan(originID, destinationID, departAfter, planner.Options{
+		MaxTransfers: s.cfg().Planner.MaxTransfers,
+		MinTransfer:  time.Duration(s.cfg().Planner.MinTransferMinutes) * time.Minute,
+		MaxResults:   maxItineraries,
+	}), nil
+}
+
+// sendPlan 为设置了目的站的监控配置档发送行程规划
+func (s *Scheduler) sendPlan(watch config.WatchConfig) {
+	itineraries, err := s.planJourney(s.ctx, watch.Operator, watch.StationID, watch.DestinationStationID, time.Now())
+	if err != nil {
+		logrus.WithError(err).WithField("watch", watch.Name).Warn("Failed to plan journey")
+		return
+	}
+
+	destination := watch.DestinationStationID
+	if station, err := s.tdxClient.FindStationContext(s.ctx, watch.Operator, destination); err == nil {
+		destination = station.StationName.ZhTw
+	}
+
+	if err := s.tgBot.SendItinerariesContext(s.ctx, itineraries, watch.Name, destination); err != nil {
+		logrus.WithError(err).Error("Failed to send journey plan")
+	}
+}
+
+// handlePlan 处理 /plan [THSR] <起站> <讫站> [HH:MM]
+func (s *Scheduler) handlePlan(ctx context.Context, args []string) (string, error) {
+	op, args := splitOperator(args)
+	if len(args) < 2 || len(args) > 3 {
+		return "用法: /plan [THSR] &lt;起站&gt; &lt;讫站&gt; [HH:MM]\n例如: /plan 竹北 富岡 18:30", nil
+	}
+
+	origin, err := s.tdxClient.FindStationContext(ctx, op, args[0])
+	if err != nil {
+		return "", err
+	}
+	destination, err := s.tdxClient.FindStationContext(ctx, op, args[1])
+	if err != nil {
+		return "", err
+	}
+
+	departAfter := time.Now().In(tdx.Location)
+	if len(args) == 3 {
+		clock, err := time.Parse("15:04", args[2])
+		if err != nil {
+			return "", fmt.Errorf("invalid time %q, expected HH:MM", args[2])
+		}
+		departAfter = time.Date(departAfter.Year(), departAfter.Month(), departAfter.Day(),
+			clock.Hour(), clock.Minute(), 0, 0, departAfter.Location())
+	}
+
+	itineraries, err := s.planJourney(ctx, op, origin.StationID, destination.StationID, departAfter)
+	if err != nil {
+		return "", err
+	}
+
+	return telegram.FormatItineraries(itineraries, origin.StationName.ZhTw, destination.StationName.ZhTw), nil
+}
@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/planner"
+	"tg-rail-shouting/internal/store"
+	"tg-rail-shouting/internal/tdx"
+	"tg-rail-shouting/internal/telegram"
+)
+
+// recommend 在 arriveBy 之前到达的直达车次中，找出按历史延误仍有 confidence 把握准时到达、
+// 且出发最晚的车次。没有车次达到信心水准时，返回把握最高的车次。
+// 延误记录只有监控的起站的出发延误，以此近似到达讫站时的延误，需要起站有监控配置档才有记录
+func (s *Scheduler) recommend(ctx context.Context, originID, destinationID string, arriveBy time.Time, confidence float64) (*telegram.Recommendation, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("delay store is not enabled")
+	}
+
+	arriveBy = arriveBy.In(tdx.Location)
+	timetables, err := s.tdxClient.GetDailyTimetableContext(ctx, tdx.OperatorTRA, arriveBy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(tdx.Location)
+	since := now.AddDate(0, 0, -7*s.cfg().Store.StatsWeeks)
+	legs := planner.New(arriveBy, timetables).DirectLegs(originID, destinationID)
+
+	var best *telegram.Recommendation
+	for i := len(legs) - 1; i >= 0; i-- {
+		leg := legs[i]
+		if leg.Arrival.After(arriveBy) || leg.Departure.Before(now) {
+			continue
+		}
+
+		observations, err := s.store.Observations(store.Query{Since: since, TrainNo: leg.TrainNo, StationID: originID})
+		if err != nil {
+			return nil, err
+		}
+		slack := int(arriveBy.Sub(leg.Arrival).Minutes())
+		probability, samples := store.ArrivalProbability(observations, slack)
+		if samples < s.cfg().Recommend.MinSamples {
+			continue
+		}
+
+		candidate := &telegram.Recommendation{
+			Leg:         leg,
+			ArriveBy:    arriveBy,
+			Probability: probability,
+			Samples:     samples,
+			Confident:   probability >= confidence,
+		}
+		if candidate.Confident {
+			return candidate, nil
+		}
+		if best == nil || probability > best.Probability {
+			best = candidate
+		}
+	}
+
+	return best, nil
+}
+
+// arriveByToday 将 "HH:MM" 换算成今天（台湾时间）的时间
+func arriveByToday(clock string) (time.Time, error) {
+	now := time.Now().In(tdx.Location)
+	arriveBy, ok := tdx.StopTimeOn(now, clock)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	return arriveBy, nil
+}
+
+// boardRecommendation 为设置了目的站的台铁监控配置档产生列车信息中的推荐行，
+// 未设置 RECOMMEND_ARRIVE_BY 或没有推荐时返回空字符串
+func (s *Scheduler) boardRecommendation(watch config.WatchConfig) string {
+	if s.store == nil || s.cfg().Recommend.ArriveBy == "" ||
+		watch.Operator != tdx.OperatorTRA || watch.DestinationStationID == "" {
+		return ""
+	}
+
+	arriveBy, err := arriveByToday(s.cfg().Recommend.ArriveBy)
+	if err != nil {
+		return ""
+	}
+
+	rec, err := s.recommend(s.ctx, watch.StationID, watch.DestinationStationID, arriveBy, float64(s.cfg().Recommend.Confidence)/100)
+	if err != nil {
+		logrus.WithError(err).WithField("watch", watch.Name).Warn("Failed to recommend train")
+		return ""
+	}
+	if rec == nil {
+		return ""
+	}
+	return telegram.FormatRecommendationLine(*rec)
+}
+
+// handleRecommend 处理 /recommend <起站> <讫站> <HH:MM> [信心%]
+func (s *Scheduler) handleRecommend(ctx context.Context, args []string) (string, error) {
+	if len(args) < 3 || len(args) > 4 {
+		return "用法: /recommend &lt;起站&gt; &lt;讫站&gt; &lt;HH:MM&gt; [信心%]\n例如: /recommend 竹北 富岡 19:30 90", nil
+	}
+	if s.store == nil {
+		return "未启用延误记录 (STORE_PATH)", nil
+	}
+
+	origin, err := s.tdxClient.FindStationContext(ctx, tdx.OperatorTRA, args[0])
+	if err != nil {
+		return "", err
+	}
+	destination, err := s.tdxClient.FindStationContext(ctx, tdx.OperatorTRA, args[1])
+	if err != nil {
+		return "", err
+	}
+	arriveBy, err := arriveByToday(args[2])
+	if err != nil {
+		return "", err
+	}
+
+	confidence := s.cfg().Recommend.Confidence
+	if len(args) == 4 {
+		n, err := strconv.Atoi(strings.TrimSuffix(args[3], "%"))
+		if err != nil || n <= 0 || n > 100 {
+			return "", fmt.Errorf("invalid confidence %q, expected 1-100", args[3])
+		}
+		confidence = n
+	}
+
+	rec, err := s.recommend(ctx, origin.StationID, destination.StationID, arriveBy, float64(confidence)/100)
+	if err != nil {
+		return "", err
+	}
+	return telegram.FormatRecommendation(rec, origin.StationName.ZhTw, destination.StationName.ZhTw, arriveBy, confidence), nil
+}
@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+)
+
+// Reload 套用重新加载的配置：按名称比较监控配置档，增删改对应的排程工作。
+// 检查记录与座位状态会保留，正在执行的检查不受影响
+func (s *Scheduler) Reload(cfg *config.Config) error {
+	previous := s.cfg()
+
+	s.configMu.Lock()
+	s.config = cfg
+	s.configMu.Unlock()
+
+	before := make(map[string]config.WatchConfig, len(previous.Watches))
+	for _, watch := range previous.Watches {
+		before[watch.Name] = watch
+	}
+
+	var firstErr error
+	keep := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for _, watch := range cfg.Watches {
+		old, existed := before[watch.Name]
+		delete(before, watch.Name)
+
+		switch {
+		case !existed:
+			logrus.WithField("watch", watch.Name).Info("Watch added")
+		case !reflect.DeepEqual(old, watch):
+			logrus.WithField("watch", watch.Name).Info("Watch updated")
+			s.removeJobsFor(watch.Name)
+		default:
+			continue
+		}
+		keep(s.addJobsFor(watch))
+	}
+
+	for name := range before {
+		logrus.WithField("watch", name).Info("Watch removed")
+		s.removeJobsFor(name)
+	}
+
+	if previous.Report.Cron != cfg.Report.Cron {
+		logrus.WithField("schedule", cfg.Report.Cron).Info("Weekly report schedule changed")
+		s.cron.Remove(s.reportJob)
+		keep(s.addReportJob())
+	}
+
+	// 每日请求上限与限流由调用方直接套用，其余 TDX 配置需要重新启动
+	tdxBefore := previous.TDX
+	tdxBefore.DailyQuota = cfg.TDX.DailyQuota
+	tdxBefore.RateLimit = cfg.TDX.RateLimit
+	tdxBefore.RateBurst = cfg.TDX.RateBurst
+	tdxBefore.AnonymousRateLimit = cfg.TDX.AnonymousRateLimit
+	tdxBefore.AnonymousRateBurst = cfg.TDX.AnonymousRateBurst
+	for section, changed := range map[string]bool{
+		"tdx":       tdxBefore != cfg.TDX,
+		"telegram":  previous.Telegram != cfg.Telegram,
+		"http":      previous.HTTP != cfg.HTTP,
+		"store":     previous.Store.Path != cfg.Store.Path,
+		"transport": previous.Transport != cfg.Transport,
+	} {
+		if changed {
+			logrus.WithField("section", section).Warn("Configuration change requires a restart to take effect")
+		}
+	}
+
+	return firstErr
+}
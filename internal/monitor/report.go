@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/chart"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/store"
+	"tg-rail-shouting/internal/tdx"
+	"tg-rail-shouting/internal/telegram"
+)
+
+// sendWeeklyReport 汇总最近 7 天各监控配置档的延误记录并发送到聊天
+func (s *Scheduler) sendWeeklyReport() {
+	if s.store == nil {
+		logrus.Warn("Delay store is not enabled, skipping weekly report")
+		return
+	}
+
+	to := time.Now().In(tdx.Location)
+	from := to.AddDate(0, 0, -7)
+
+	var sections []telegram.ReportSection
+	for _, watch := range s.cfg().Watches {
+		if watch.Type == config.WatchTypeSeats || watch.Operator != tdx.OperatorTRA {
+			continue
+		}
+
+		section, err := s.reportSection(watch, from)
+		if err != nil {
+			logrus.WithError(err).WithField("watch", watch.Name).Error("Failed to build weekly report")
+			continue
+		}
+		sections = append(sections, section)
+	}
+
+	if err := s.tgBot.SendMessageContext(s.ctx, telegram.FormatWeeklyReport(from, to, sections)); err != nil {
+		logrus.WithError(err).Error("Failed to send weekly report")
+		return
+	}
+
+	if !s.cfg().Report.Chart {
+		return
+	}
+	for _, section := range sections {
+		s.sendDelayChart(section)
+	}
+}
+
+// reportSection 统计单一监控配置档自 from 起的延误记录
+func (s *Scheduler) reportSection(watch config.WatchConfig, from time.Time) (telegram.ReportSection, error) {
+	observations, err := s.store.Observations(store.Query{Since: from, Watch: watch.Name})
+	if err != nil {
+		return telegram.ReportSection{}, err
+	}
+
+	title := watch.Name
+	if watch.DestinationStationID != "" {
+		destination := watch.DestinationStationID
+		if station, err := s.tdxClient.FindStationContext(s.ctx, watch.Operator, destination); err == nil {
+			destination = station.StationName.ZhTw
+		}
+		title = fmt.Sprintf("%s → %s", watch.Name, destination)
+	}
+
+	section := telegram.ReportSection{
+		Title:  title,
+		Trains: store.ByTrain(observations),
+		Hours:  store.ByHour(observations),
+	}
+	for _, obs := range observations {
+		if obs.Cancelled() {
+			section.Cancelled = append(section.Cancelled, obs)
+		}
+	}
+	return section, nil
+}
+
+// sendDelayChart 发送各时段平均延误的长条图
+func (s *Scheduler) sendDelayChart(section telegram.ReportSection) {
+	var bars []chart.Bar
+	for _, hour := range section.Hours {
+		if hour.Punctuality.Count == 0 {
+			continue
+		}
+		bars = append(bars, chart.Bar{Label: fmt.Sprintf("%02d", hour.Hour), Value: hour.Punctuality.AvgDelay})
+	}
+	if len(bars) == 0 {
+		return
+	}
+
+	png, err := chart.BarChart("Average delay (min) by scheduled hour", bars)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to render delay chart")
+		return
+	}
+
+	caption := fmt.Sprintf("🕖 %s 各时段平均延误（分钟）", section.Title)
+	if err := s.tgBot.SendPhotoContext(s.ctx, png, caption); err != nil {
+		logrus.WithError(err).Error("Failed to send delay chart")
+	}
+}
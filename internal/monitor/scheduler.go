@@ -3,61 +3,105 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/health"
+	"tg-rail-shouting/internal/metrics"
+	"tg-rail-shouting/internal/redact"
+	"tg-rail-shouting/internal/store"
 	"tg-rail-shouting/internal/tdx"
 	"tg-rail-shouting/internal/telegram"
 )
 
 type Scheduler struct {
 	cron      *cron.Cron
+	configMu  sync.RWMutex
 	config    *config.Config
+	jobs      map[string][]cron.EntryID // 每个监控配置档的排程工作
+	reportJob cron.EntryID
 	tdxClient *tdx.Client
 	tgBot     *telegram.Bot
-	ctx       context.Context
+	ctx       context.Context // 停止时取消，进行中的 TDX 与 Telegram 请求随之中断
 	cancel    context.CancelFunc
+	loopDone  chan struct{}
+	seats     *seatTracker
+	history   *history
+	store     *store.Store // 可选：保存延误记录，nil 表示不记录
 }
 
 func NewScheduler(cfg *config.Config, tdxClient *tdx.Client, tgBot *telegram.Bot) *Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
 	
 	return &Scheduler{
-		cron:      cron.New(cron.WithLocation(time.Local)),
+		cron:      cron.New(cron.WithLocation(time.Local), cron.WithParser(config.CronParser)),
 		config:    cfg,
 		tdxClient: tdxClient,
 		tgBot:     tgBot,
 		ctx:       ctx,
 		cancel:    cancel,
+		loopDone:  make(chan struct{}),
+		jobs:      make(map[string][]cron.EntryID),
+		seats:     newSeatTracker(),
+		history:   newHistory(),
 	}
 }
 
+// cfg 返回当前的配置，配置可能在重新加载时被替换
+func (s *Scheduler) cfg() *config.Config {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
+// SetStore 设置保存延误记录的数据库
+func (s *Scheduler) SetStore(st *store.Store) {
+	s.store = st
+}
+
 func (s *Scheduler) Start() error {
-	cronExpr := fmt.Sprintf("*/%d * * * *", s.config.Monitor.IntervalMinutes)
-	
-	_, err := s.cron.AddFunc(cronExpr, func() {
-		if s.shouldMonitor() {
-			s.checkTrains()
-		}
-	})
+	if err := s.addWatchJobs(); err != nil {
+		return err
+	}
 	
-	if err != nil {
-		return fmt.Errorf("failed to add cron job: %w", err)
+	if err := s.addReportJob(); err != nil {
+		return err
 	}
 	
+	s.registerCommands()
+	go s.tgBot.Poll(s.ctx)
+	
 	s.cron.Start()
+	health.SetSchedulerRunning(true)
 	logrus.Info("Scheduler started")
 	
-	go s.runInitialCheck()
+	go s.pollLoop()
 	
 	return nil
 }
 
+// stopTimeout 是停止时等待进行中的检查结束的上限
+const stopTimeout = 10 * time.Second
+
+// Stop 取消进行中的请求，并等待排程工作与自适应检查结束（最多 stopTimeout），需在 Start 之后调用
 func (s *Scheduler) Stop() {
-	s.cron.Stop()
 	s.cancel()
+	health.SetSchedulerRunning(false)
+	cronDone := s.cron.Stop().Done()
+
+	timeout := time.NewTimer(stopTimeout)
+	defer timeout.Stop()
+	for _, done := range []<-chan struct{}{cronDone, s.loopDone} {
+		select {
+		case <-done:
+		case <-timeout.C:
+			logrus.Warn("Timed out waiting for running checks to stop")
+			return
+		}
+	}
 	logrus.Info("Scheduler stopped")
 }
 
@@ -67,7 +111,11 @@ func (s *Scheduler) shouldMonitor() bool {
 }
 
 func (s *Scheduler) runInitialCheck() {
-	time.Sleep(3 * time.Second)
+	select {
+	case <-s.ctx.Done():
+		return
+	case <-time.After(3 * time.Second):
+	}
 	
 	logrus.Info("Running initial train check to verify service...")
 	s.checkTrainsForce(true)
@@ -86,14 +134,39 @@ func (s *Scheduler) checkTrainsForce(isInitial bool) {
 	default:
 	}
 	
+	// 启动时检查所有监控配置档，之后只检查使用自适应间隔的配置档
+	watches := s.cfg().Watches
+	if !isInitial {
+		watches = s.adaptiveWatches()
+	}
+	
+	start := time.Now()
+	for _, watch := range watches {
+		s.checkWatch(watch, isInitial)
+	}
+	metrics.SchedulerTickDuration.Observe(time.Since(start).Seconds())
+}
+
+func (s *Scheduler) checkWatch(watch config.WatchConfig, isInitial bool) {
+	if watch.Type == config.WatchTypeSeats {
+		s.checkSeats(watch)
+		return
+	}
+	
 	if isInitial {
-		logrus.Info("Initial API test - checking trains for Zhubei Station...")
+		logrus.WithField("watch", watch.Name).Info("Initial API test - checking trains...")
 	} else {
-		logrus.Info("Scheduled check - checking trains for Zhubei Station...")
+		logrus.WithField("watch", watch.Name).Info("Scheduled check - checking trains...")
 	}
 	
-	trains, err := s.tdxClient.GetTrainTimetable(s.config.Station.ZhubeiStationID, s.config.Station.TargetDirection)
+	trains, err := s.tdxClient.GetLiveBoardContext(s.ctx, watch.Operator, watch.StationID, watch.Direction)
+	if s.ctx.Err() != nil {
+		// 调度器正在停止
+		return
+	}
 	if err != nil {
+		health.RecordCheck(err)
+		s.history.record(watch, nil, err)
 		logrus.WithError(err).Error("Failed to get train timetable")
 		if isInitial {
 			s.sendInitialErrorMessage(err)
@@ -103,6 +176,34 @@ func (s *Scheduler) checkTrainsForce(isInitial bool) {
 		return
 	}
 	
+	// 过滤前记录，统计不受监控配置档的过滤条件影响
+	s.recordObservations(watch, trains)
+	
+	if err := s.tdxClient.AnnotateAmenitiesContext(s.ctx, watch.Operator, trains); err != nil {
+		if s.ctx.Err() != nil {
+			return
+		}
+		if watch.Filter.RequiresAmenities() {
+			// 没有服务标记时过滤会去掉所有列车，当作检查失败而不是没有列车
+			err = fmt.Errorf("failed to get train amenities: %w", err)
+			health.RecordCheck(err)
+			s.history.record(watch, nil, err)
+			logrus.WithError(err).Error("Failed to get train amenities")
+			if isInitial {
+				s.sendInitialErrorMessage(err)
+			} else {
+				s.sendErrorMessage(err)
+			}
+			return
+		}
+		logrus.WithError(err).Warn("Failed to get train amenities")
+	}
+	health.RecordCheck(nil)
+	
+	trains = filterTrains(trains, watch.Filter)
+	metrics.TrainsFound.WithLabelValues(watch.Name).Set(float64(len(trains)))
+	s.history.record(watch, trains, nil)
+	
 	if len(trains) == 0 {
 		logrus.Info("No trains found for current time")
 		if isInitial {
@@ -111,10 +212,15 @@ func (s *Scheduler) checkTrainsForce(isInitial bool) {
 		return
 	}
 	
-	s.processTrains(trains, isInitial)
+	s.processTrains(trains, watch, isInitial)
+	
+	// 行程规划只在首次检查时发送，之后的检查不重复推送
+	if isInitial && watch.DestinationStationID != "" {
+		s.sendPlan(watch)
+	}
 }
 
-func (s *Scheduler) processTrains(trains []tdx.TrainInfo, isInitial bool) {
+func (s *Scheduler) processTrains(trains []tdx.TrainInfo, watch config.WatchConfig, isInitial bool) {
 	// 不再過濾時間，直接取最多5個列車
 	var processedTrains []tdx.TrainInfo
 	maxTrains := 5
@@ -125,7 +231,7 @@ func (s *Scheduler) processTrains(trains []tdx.TrainInfo, isInitial bool) {
 		}
 		
 		// 為每個列車獲取完整路線信息
-		route, err := s.tdxClient.GetTrainRoute(train.TrainNo)
+		route, err := s.tdxClient.GetRouteContext(s.ctx, watch.Operator, train.TrainNo)
 		if err != nil {
 			logrus.WithError(err).WithField("train", train.TrainNo).Warn("Failed to get train route")
 			// 如果獲取路線失敗，仍然添加基本信息
@@ -148,14 +254,17 @@ func (s *Scheduler) processTrains(trains []tdx.TrainInfo, isInitial bool) {
 	
 	logrus.WithField("count", len(processedTrains)).Info("Found trains to display")
 	
-	var stationName string
+	// 票价资料目前只有台铁
+	if watch.Operator == tdx.OperatorTRA {
+		s.annotateFares(processedTrains, watch.StationID)
+	}
+	
+	stationName := watch.Name
 	if isInitial {
-		stationName = "竹北 (服务测试)"
-	} else {
-		stationName = "竹北"
+		stationName = watch.Name + " (服务测试)"
 	}
 	
-	if err := s.tgBot.SendTrainInfo(processedTrains, stationName); err != nil {
+	if err := s.tgBot.SendTrainInfoContext(s.ctx, processedTrains, stationName, s.boardRecommendation(watch)); err != nil {
 		logrus.WithError(err).Error("Failed to send train info")
 		return
 	}
@@ -163,6 +272,24 @@ func (s *Scheduler) processTrains(trains []tdx.TrainInfo, isInitial bool) {
 	// 不再需要 sendDetailedInfo，因為主要訊息已經包含完整路線
 }
 
+// annotateFares 为每个列车填上到票价目的站的成人票价（未配置时跳过）
+func (s *Scheduler) annotateFares(trains []tdx.TrainInfo, originStationID string) {
+	destinationID := s.cfg().Station.FareDestinationID
+	if destinationID == "" {
+		return
+	}
+	
+	fares, err := s.tdxClient.GetODFareContext(s.ctx, originStationID, destinationID)
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to get OD fare")
+		return
+	}
+	
+	for i := range trains {
+		trains[i].Fare = fares.AdultFare(trains[i].TrainTypeCode)
+	}
+}
+
 func (s *Scheduler) sendDetailedInfo(trains []tdx.TrainInfo) {
 	if len(trains) == 0 {
 		return
@@ -175,7 +302,7 @@ func (s *Scheduler) sendDetailedInfo(trains []tdx.TrainInfo) {
 			break
 		}
 		
-		route, reachFugang, err := s.tdxClient.FindRouteToFugang(train.TrainNo, s.config.Station.ZhubeiStationID)
+		route, reachFugang, err := s.tdxClient.FindRouteToFugangContext(s.ctx, train.TrainNo, s.cfg().Station.ZhubeiStationID)
 		if err != nil {
 			logrus.WithError(err).WithField("train", train.TrainNo).Warn("Failed to get route to Fugang")
 			continue
@@ -197,29 +324,29 @@ func (s *Scheduler) sendDetailedInfo(trains []tdx.TrainInfo) {
 	}
 	
 	if len(trainsToFugang) > 0 {
-		if err := s.tgBot.SendDetailedTrainInfo(trainsToFugang, "竹北", "富岡"); err != nil {
+		if err := s.tgBot.SendDetailedTrainInfoContext(s.ctx, trainsToFugang, "竹北", "富岡"); err != nil {
 			logrus.WithError(err).Error("Failed to send detailed train info")
 		}
 	}
 }
 
 func (s *Scheduler) sendErrorMessage(err error) {
-	message := fmt.Sprintf("❌ 获取列车信息失败\n\n错误: %v\n时间: %s", err, time.Now().Format("2006-01-02 15:04:05"))
+	message := fmt.Sprintf("❌ 获取列车信息失败\n\n错误: %v\n时间: %s", redact.Error(err), time.Now().Format("2006-01-02 15:04:05"))
 	
-	if sendErr := s.tgBot.SendMessage(message); sendErr != nil {
+	if sendErr := s.tgBot.SendMessageContext(s.ctx, message); sendErr != nil {
 		logrus.WithError(sendErr).Error("Failed to send error message")
 	}
 }
 
 func (s *Scheduler) sendInitialErrorMessage(err error) {
 	message := fmt.Sprintf("⚠️ 台铁监控服务已启动，但API测试失败\n\n监控时间: %d:00 - %d:00\n检查间隔: %d分钟\n监控站点: 竹北站\n\n❌ API测试错误: %v\n时间: %s\n\n服务将继续运行，稍后会重试...", 
-		s.config.Monitor.StartHour, 
-		s.config.Monitor.EndHour,
-		s.config.Monitor.IntervalMinutes,
-		err, 
+		s.cfg().Monitor.StartHour, 
+		s.cfg().Monitor.EndHour,
+		s.cfg().Monitor.IntervalMinutes,
+		redact.Error(err), 
 		time.Now().Format("2006-01-02 15:04:05"))
 	
-	if sendErr := s.tgBot.SendMessage(message); sendErr != nil {
+	if sendErr := s.tgBot.SendMessageContext(s.ctx, message); sendErr != nil {
 		logrus.WithError(sendErr).Error("Failed to send initial error message")
 	}
 }
@@ -227,21 +354,21 @@ func (s *Scheduler) sendInitialErrorMessage(err error) {
 func (s *Scheduler) sendNoTrainsMessage() {
 	now := time.Now()
 	message := fmt.Sprintf("✅ 台铁监控服务已启动并完成API测试\n\n监控时间: %d:00 - %d:00\n检查间隔: %d分钟\n监控站点: 竹北站\n\n🚄 API测试结果: 当前时间(%s)没有列车信息\n这很正常，服务将在监控时间内定期检查\n\n服务运行正常 ✅", 
-		s.config.Monitor.StartHour, 
-		s.config.Monitor.EndHour,
-		s.config.Monitor.IntervalMinutes,
+		s.cfg().Monitor.StartHour, 
+		s.cfg().Monitor.EndHour,
+		s.cfg().Monitor.IntervalMinutes,
 		now.Format("15:04"))
 	
-	if sendErr := s.tgBot.SendMessage(message); sendErr != nil {
+	if sendErr := s.tgBot.SendMessageContext(s.ctx, message); sendErr != nil {
 		logrus.WithError(sendErr).Error("Failed to send no trains message")
 	}
 }
 
 func (s *Scheduler) SendTestMessage() error {
 	message := fmt.Sprintf("✅ 台铁监控服务已启动\n\n监控时间: %d:00 - %d:00\n检查间隔: %d分钟\n监控站点: 竹北站\n\n正在进行API连接测试...", 
-		s.config.Monitor.StartHour, 
-		s.config.Monitor.EndHour,
-		s.config.Monitor.IntervalMinutes)
+		s.cfg().Monitor.StartHour, 
+		s.cfg().Monitor.EndHour,
+		s.cfg().Monitor.IntervalMinutes)
 	
-	return s.tgBot.SendMessage(message)
+	return s.tgBot.SendMessageContext(s.ctx, message)
 }
\ No newline at end of file
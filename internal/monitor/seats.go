@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/health"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// seatTracker 按乘车日期记录每个车次、每种车厢上一次看到的座位状态，用于判断状态转变
+type seatTracker struct {
+	mu     sync.Mutex
+	states map[string]map[string]string // 乘车日期 (YYYY-MM-DD) -> 键 -> 状态
+}
+
+func newSeatTracker() *seatTracker {
+	return &seatTracker{states: make(map[string]map[string]string)}
+}
+
+// update 记录新状态，只有从售完变为有位时返回 true，同一次转变只通知一次
+func (t *seatTracker) update(date, key, status string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	states, ok := t.states[date]
+	if !ok {
+		states = make(map[string]string)
+		t.states[date] = states
+	}
+	previous, seen := states[key]
+	states[key] = status
+	return seen && previous == tdx.SeatSoldOut && status != tdx.SeatSoldOut && status != ""
+}
+
+// prune 移除 today 之前的乘车日期，避免记录随运行时间无限增长
+func (t *seatTracker) prune(today string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for date := range t.states {
+		if date < today {
+			delete(t.states, date)
+		}
+	}
+}
+
+// seatChange 是一次从售完变为有位的转变
+type seatChange struct {
+	Seats  tdx.THSRODSeats
+	Class  string // 标准车厢 / 商务车厢
+	Status string
+}
+
+func (s *Scheduler) checkSeats(watch config.WatchConfig) {
+	logrus.WithField("watch", watch.Name).Info("Checking THSR available seats...")
+
+	wanted := make(map[string]bool)
+	for _, trainNo := range watch.TrainNos {
+		wanted[trainNo] = true
+	}
+
+	s.seats.prune(time.Now().In(tdx.Location).Format("2006-01-02"))
+
+	var changes []seatChange
+	var checkErr error
+	for _, date := range watch.SeatDates(time.Now()) {
+		seats, err := s.tdxClient.GetTHSRAvailableSeatsContext(s.ctx, watch.StationID, watch.DestinationStationID, date)
+		if s.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logrus.WithError(err).WithField("watch", watch.Name).Error("Failed to get THSR available seats")
+			checkErr = err
+			continue
+		}
+
+		for _, seat := range seats {
+			if len(wanted) > 0 && !wanted[seat.TrainNo] {
+				continue
+			}
+
+			key := fmt.Sprintf("%s:%s", watch.Name, seat.TrainNo)
+			if s.seats.update(seat.TrainDate, key+":standard", seat.StandardSeatStatus) {
+				changes = append(changes, seatChange{Seats: seat, Class: "标准车厢", Status: seat.StandardSeatStatus})
+			}
+			if s.seats.update(seat.TrainDate, key+":business", seat.BusinessSeatStatus) {
+				changes = append(changes, seatChange{Seats: seat, Class: "商务车厢", Status: seat.BusinessSeatStatus})
+			}
+		}
+	}
+
+	health.RecordCheck(checkErr)
+
+	if len(changes) == 0 {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"watch":   watch.Name,
+		"changes": len(changes),
+	}).Info("THSR seats became available")
+
+	if err := s.tgBot.SendMessageContext(s.ctx, formatSeatChanges(watch, changes)); err != nil {
+		logrus.WithError(err).Error("Failed to send seat notification")
+	}
+}
+
+func formatSeatChanges(watch config.WatchConfig, changes []seatChange) string {
+	first := changes[0].Seats
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("💺 <b>%s 高铁有位了</b>\n%s → %s\n\n",
+		watch.Name, first.OriginStationName.ZhTw, first.DestinationStationName.ZhTw))
+
+	for _, change := range changes {
+		status := "尚有座位"
+		if change.Status == tdx.SeatLimited {
+			status = "座位有限"
+		}
+		message.WriteString(fmt.Sprintf("🚄 %s %s次 %s → %s\n    %s: %s\n",
+			change.Seats.TrainDate,
+			change.Seats.TrainNo,
+			change.Seats.DepartureTime,
+			change.Seats.ArrivalTime,
+			change.Class,
+			status))
+	}
+
+	return message.String()
+}
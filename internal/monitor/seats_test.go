@@ -0,0 +1,36 @@
+package monitor
+
+import (
+	"testing"
+
+	"tg-rail-shouting/internal/tdx"
+)
+
+func TestSeatTracker(t *testing.T) {
+	tracker := newSeatTracker()
+
+	steps := []struct {
+		date, status string
+		want         bool
+	}{
+		{"2024-05-06", tdx.SeatSoldOut, false},   // 第一次看到，不通知
+		{"2024-05-06", tdx.SeatAvailable, true},  // 售完变为有位
+		{"2024-05-06", tdx.SeatAvailable, false}, // 同一次转变只通知一次
+		{"2024-05-06", tdx.SeatSoldOut, false},
+		{"2024-05-06", "", false}, // 没有资料不算有位
+		{"2024-05-07", tdx.SeatLimited, false},
+	}
+	for i, step := range steps {
+		if got := tracker.update(step.date, "watch:123:standard", step.status); got != step.want {
+			t.Errorf("step %d: update(%s, %q) = %v, want %v", i, step.date, step.status, got, step.want)
+		}
+	}
+
+	tracker.prune("2024-05-07")
+	if _, ok := tracker.states["2024-05-06"]; ok {
+		t.Error("past date was not pruned")
+	}
+	if _, ok := tracker.states["2024-05-07"]; !ok {
+		t.Error("today was pruned")
+	}
+}
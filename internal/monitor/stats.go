@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/store"
+	"tg-rail-shouting/internal/tdx"
+	"tg-rail-shouting/internal/telegram"
+)
+
+// recordObservations 保存即时看板上各车次的延误状况（目前只有台铁提供延误信息）
+func (s *Scheduler) recordObservations(watch config.WatchConfig, trains []tdx.TrainInfo) {
+	if s.store == nil || watch.Operator != tdx.OperatorTRA {
+		return
+	}
+
+	now := time.Now().In(tdx.Location)
+	observations := make([]store.Observation, 0, len(trains))
+	for _, train := range trains {
+		scheduled := train.ArrivalTime
+		if scheduled == "" {
+			scheduled = train.DepartureTime
+		}
+		observations = append(observations, store.Observation{
+			Date:          now.Format("2006-01-02"),
+			Operator:      string(watch.Operator),
+			TrainNo:       train.TrainNo,
+			TrainType:     train.TrainType,
+			StationID:     watch.StationID,
+			Watch:         watch.Name,
+			ScheduledTime: scheduled,
+			DelayMinutes:  train.DelayMinutes,
+			Platform:      train.Platform,
+			RunningStatus: train.RunningStatus,
+			ObservedAt:    now,
+		})
+	}
+
+	if err := s.store.Record(observations); err != nil {
+		logrus.WithError(err).Warn("Failed to record delay observations")
+	}
+}
+
+// TrainStats 统计某车次最近 weeks 周的准点情况，weeks 不大于 0 时使用配置的默认值
+func (s *Scheduler) TrainStats(trainNo string, weeks int) ([]store.WeekdayStats, store.Punctuality, error) {
+	if s.store == nil {
+		return nil, store.Punctuality{}, fmt.Errorf("delay store is not enabled")
+	}
+	if weeks <= 0 {
+		weeks = s.cfg().Store.StatsWeeks
+	}
+
+	since := time.Now().In(tdx.Location).AddDate(0, 0, -7*weeks)
+	observations, err := s.store.Observations(store.Query{Since: since, TrainNo: trainNo})
+	if err != nil {
+		return nil, store.Punctuality{}, err
+	}
+	observations = store.Distinct(observations)
+	return store.ByWeekday(observations), store.Summarize(observations), nil
+}
+
+// handleStats 处理 /stats <车次> [周数]
+func (s *Scheduler) handleStats(ctx context.Context, args []string) (string, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return "用法: /stats &lt;车次&gt; [周数]\n例如: /stats 1234 4", nil
+	}
+	if s.store == nil {
+		return "未启用延误记录 (STORE_PATH)", nil
+	}
+
+	weeks := s.cfg().Store.StatsWeeks
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("invalid number of weeks %q", args[1])
+		}
+		weeks = n
+	}
+
+	byWeekday, overall, err := s.TrainStats(args[0], weeks)
+	if err != nil {
+		return "", err
+	}
+	return telegram.FormatPunctuality(args[0], weeks, byWeekday, overall), nil
+}
@@ -0,0 +1,132 @@
+package monitor
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// 在这个时间之前，前一天发车的车次可能还没有经过监控车站
+const overnightCutoff = 6 * time.Hour
+
+// UpcomingDeparture 是监控车站按每日时刻表即将出发的一班车
+type UpcomingDeparture struct {
+	Train          tdx.TrainInfo
+	Departure      time.Time
+	Arrival        time.Time // 到达目的站的时间，未设目的站时为到达终点站的时间
+	ArrivalStation string
+}
+
+// UpcomingDepartures 按每日时刻表列出监控配置档在 [from, from+within) 之间出发的列车，
+// 套用配置档的方向与过滤条件；设置了目的站时只列出会停靠目的站的车次
+func (s *Scheduler) UpcomingDepartures(ctx context.Context, watch config.WatchConfig, from time.Time, within time.Duration) ([]UpcomingDeparture, error) {
+	from = from.In(tdx.Location)
+	until := from.Add(within)
+
+	// 查询区间可能跨越午夜，逐日取得时刻表；清晨时前一天发车的车次可能还没到本站，也要取前一天的时刻表
+	first := startOfDay(from)
+	if from.Sub(first) < overnightCutoff {
+		first = first.AddDate(0, 0, -1)
+	}
+
+	var departures []UpcomingDeparture
+	for day := first; day.Before(until); day = day.AddDate(0, 0, 1) {
+		timetables, err := s.tdxClient.GetDailyTimetableContext(ctx, watch.Operator, day)
+		if err != nil && day.Before(startOfDay(from)) && ctx.Err() == nil {
+			// 前一天的时刻表只用来补上过午夜的车次，取不到时仍列出当天的车次
+			logrus.WithError(err).WithField("watch", watch.Name).Warn("Failed to get previous day's timetable")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tt := range timetables {
+			departure, ok := upcomingDeparture(day, tt, watch)
+			if !ok || departure.Departure.Before(from) || !departure.Departure.Before(until) {
+				continue
+			}
+			if !matchesFilter(departure.Train, watch.Filter) {
+				continue
+			}
+			departures = append(departures, departure)
+		}
+	}
+
+	sort.Slice(departures, func(i, j int) bool {
+		return departures[i].Departure.Before(departures[j].Departure)
+	})
+	return departures, nil
+}
+
+func upcomingDeparture(day time.Time, tt tdx.DailyTrainTimetable, watch config.WatchConfig) (UpcomingDeparture, bool) {
+	if watch.DestinationStationID == "" && tt.TrainInfo.Direction != watch.Direction {
+		return UpcomingDeparture{}, false
+	}
+
+	train, ok := tt.TrainInfoAt(watch.StationID)
+	if !ok || len(train.Stations) < 2 {
+		return UpcomingDeparture{}, false
+	}
+	train.Operator = watch.Operator
+
+	departure, ok := tdx.StopTimeOn(day, train.DepartureTime)
+	if !ok {
+		return UpcomingDeparture{}, false
+	}
+	// 前一天发车、过午夜才到本站的车次
+	if len(tt.StopTimes) > 0 {
+		if first, ok := tdx.StopTimeOn(day, tt.StopTimes[0].DepartureTime); ok && departure.Before(first) {
+			departure = departure.AddDate(0, 0, 1)
+		}
+	}
+
+	// 默认以终点站为到达站，有目的站时必须停靠目的站
+	arrivalStop := train.Stations[len(train.Stations)-1]
+	if watch.DestinationStationID != "" {
+		found := false
+		for _, st := range tt.StopTimes {
+			if st.StationID == watch.DestinationStationID && st.StopSequence > train.StopSequence {
+				arrivalStop = tdx.StationInfo{
+					StationName:   st.StationName.ZhTw,
+					ArrivalTime:   st.ArrivalTime,
+					DepartureTime: st.DepartureTime,
+					StopSequence:  st.StopSequence,
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return UpcomingDeparture{}, false
+		}
+	}
+
+	arrivalTime := arrivalStop.ArrivalTime
+	if arrivalTime == "" {
+		arrivalTime = arrivalStop.DepartureTime
+	}
+	arrival, ok := tdx.StopTimeOn(day, arrivalTime)
+	if !ok {
+		return UpcomingDeparture{}, false
+	}
+	// 跨午夜的车次
+	for arrival.Before(departure) {
+		arrival = arrival.AddDate(0, 0, 1)
+	}
+
+	return UpcomingDeparture{
+		Train:          train,
+		Departure:      departure,
+		Arrival:        arrival,
+		ArrivalStation: arrivalStop.StationName,
+	}, true
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
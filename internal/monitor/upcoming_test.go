@@ -0,0 +1,34 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"tg-rail-shouting/internal/config"
+	"tg-rail-shouting/internal/tdx"
+)
+
+func TestUpcomingDepartureAfterMidnight(t *testing.T) {
+	day := time.Date(2024, 5, 6, 0, 0, 0, 0, tdx.Location)
+	watch := config.WatchConfig{StationID: "B", Operator: tdx.OperatorTRA, Direction: 1}
+	tt := tdx.DailyTrainTimetable{
+		TrainInfo: tdx.GeneralTrainInfo{TrainNo: "1", Direction: 1},
+		StopTimes: []tdx.StopTime{
+			{StopSequence: 1, StationID: "A", DepartureTime: "23:30", ArrivalTime: "23:30"},
+			{StopSequence: 2, StationID: "B", DepartureTime: "00:15", ArrivalTime: "00:14"},
+			{StopSequence: 3, StationID: "C", DepartureTime: "00:40", ArrivalTime: "00:40"},
+		},
+	}
+
+	// 前一天的时刻表中，过午夜才到 B 站的车次属于当天清晨
+	departure, ok := upcomingDeparture(day.AddDate(0, 0, -1), tt, watch)
+	if !ok {
+		t.Fatal("train not listed")
+	}
+	if want := day.Add(15 * time.Minute); !departure.Departure.Equal(want) {
+		t.Errorf("departure = %v, want %v", departure.Departure, want)
+	}
+	if want := day.Add(40 * time.Minute); !departure.Arrival.Equal(want) {
+		t.Errorf("arrival = %v, want %v", departure.Arrival, want)
+	}
+}
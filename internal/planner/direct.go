@@ -0,0 +1,35 @@
+package planner
+
+import "sort"
+
+// DirectLegs 返回所有先停 origin、后停 destination 的直达车次，按出发时间排序
+func (p *Planner) DirectLegs(origin, destination string) []Leg {
+	var legs []Leg
+	for _, ref := range p.byStation[origin] {
+		t := p.trips[ref.trip]
+		boarding := t.stops[ref.index]
+
+		for _, alighting := range t.stops[ref.index+1:] {
+			if alighting.stationID != destination {
+				continue
+			}
+			legs = append(legs, Leg{
+				TrainNo:         t.info.TrainNo,
+				TrainType:       t.info.TrainTypeName.ZhTw,
+				TrainTypeCode:   t.info.TrainTypeCode,
+				FromStationID:   boarding.stationID,
+				FromStationName: boarding.stationName,
+				ToStationID:     alighting.stationID,
+				ToStationName:   alighting.stationName,
+				Departure:       boarding.departure,
+				Arrival:         alighting.arrival,
+			})
+			break
+		}
+	}
+
+	sort.Slice(legs, func(i, j int) bool {
+		return legs[i].Departure.Before(legs[j].Departure)
+	})
+	return legs
+}
@@ -0,0 +1,260 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tg-rail-shouting/internal/tdx"
+)
+
+// Options 控制行程搜索
+type Options struct {
+	MaxTransfers int           // 最多转乘次数
+	MinTransfer  time.Duration // 转乘所需的最少时间
+	MaxResults   int           // 最多返回的行程数
+}
+
+// Leg 是行程中乘坐同一班车的一段
+type Leg struct {
+	TrainNo         string
+	TrainType       string
+	TrainTypeCode   string
+	FromStationID   string
+	FromStationName string
+	ToStationID     string
+	ToStationName   string
+	Departure       time.Time
+	Arrival         time.Time
+}
+
+// Itinerary 是从起站到讫站的一个完整行程
+type Itinerary struct {
+	Legs []Leg
+}
+
+func (it Itinerary) Departure() time.Time {
+	return it.Legs[0].Departure
+}
+
+func (it Itinerary) Arrival() time.Time {
+	return it.Legs[len(it.Legs)-1].Arrival
+}
+
+func (it Itinerary) Transfers() int {
+	return len(it.Legs) - 1
+}
+
+func (it Itinerary) Duration() time.Duration {
+	return it.Arrival().Sub(it.Departure())
+}
+
+// signature 用于去除重复的行程
+func (it Itinerary) signature() string {
+	parts := make([]string, 0, len(it.Legs))
+	for _, leg := range it.Legs {
+		parts = append(parts, fmt.Sprintf("%s@%s", leg.TrainNo, leg.FromStationID))
+	}
+	return strings.Join(parts, ">")
+}
+
+type stop struct {
+	stationID   string
+	stationName string
+	arrival     time.Time
+	departure   time.Time
+}
+
+type trip struct {
+	info  tdx.GeneralTrainInfo
+	stops []stop
+}
+
+type stopRef struct {
+	trip  int
+	index int
+}
+
+// Planner 基于某一天的每日时刻表搜索行程
+type Planner struct {
+	trips     []trip
+	byStation map[string][]stopRef
+}
+
+// New 用指定日期的每日时刻表建立 Planner
+func New(date time.Time, timetables []tdx.DailyTrainTimetable) *Planner {
+	p := &Planner{byStation: make(map[string][]stopRef)}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	for _, tt := range timetables {
+		stops := buildStops(day, tt.StopTimes)
+		if len(stops) < 2 {
+			continue
+		}
+
+		idx := len(p.trips)
+		p.trips = append(p.trips, trip{info: tt.TrainInfo, stops: stops})
+		for i, st := range stops {
+			p.byStation[st.stationID] = append(p.byStation[st.stationID], stopRef{trip: idx, index: i})
+		}
+	}
+
+	return p
+}
+
+// buildStops 将时刻表的 "HH:MM" 时间换算成绝对时间，跨午夜的车次自动顺延一天
+func buildStops(day time.Time, stopTimes []tdx.StopTime) []stop {
+	sorted := make([]tdx.StopTime, len(stopTimes))
+	copy(sorted, stopTimes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StopSequence < sorted[j].StopSequence
+	})
+
+	var stops []stop
+	var last time.Time
+	for _, st := range sorted {
+		arrival, okArr := tdx.StopTimeOn(day, st.ArrivalTime)
+		departure, okDep := tdx.StopTimeOn(day, st.DepartureTime)
+		if !okArr && !okDep {
+			continue
+		}
+		if !okArr {
+			arrival = departure
+		}
+		if !okDep {
+			departure = arrival
+		}
+
+		for !last.IsZero() && arrival.Before(last) {
+			arrival = arrival.Add(24 * time.Hour)
+		}
+		for departure.Before(arrival) {
+			departure = departure.Add(24 * time.Hour)
+		}
+		last = departure
+
+		stops = append(stops, stop{
+			stationID:   st.StationID,
+			stationName: st.StationName.ZhTw,
+			arrival:     arrival,
+			departure:   departure,
+		})
+	}
+
+	return stops
+}
+
+type label struct {
+	arrival time.Time
+	legs    []Leg
+}
+
+// Plan 搜索从 origin 到 destination、在 departAfter 之后出发的行程，
+// 按到达时间、转乘次数排序
+func (p *Planner) Plan(origin, destination string, departAfter time.Time, opts Options) []Itinerary {
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = 3
+	}
+
+	seen := make(map[string]bool)
+	var results []Itinerary
+
+	// 每轮取得一组最快行程后，从其首班车之后再搜索，以找出后续的可选班次
+	next := departAfter
+	for attempt := 0; attempt < opts.MaxResults*3 && len(results) < opts.MaxResults*2; attempt++ {
+		found := p.search(origin, destination, next, opts)
+		if len(found) == 0 {
+			break
+		}
+
+		earliest := found[0].Departure()
+		for _, it := range found {
+			if it.Departure().Before(earliest) {
+				earliest = it.Departure()
+			}
+			if sig := it.signature(); !seen[sig] {
+				seen[sig] = true
+				results = append(results, it)
+			}
+		}
+		next = earliest.Add(time.Minute)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if !results[i].Arrival().Equal(results[j].Arrival()) {
+			return results[i].Arrival().Before(results[j].Arrival())
+		}
+		if results[i].Transfers() != results[j].Transfers() {
+			return results[i].Transfers() < results[j].Transfers()
+		}
+		return results[i].Departure().After(results[j].Departure())
+	})
+
+	if len(results) > opts.MaxResults {
+		results = results[:opts.MaxResults]
+	}
+	return results
+}
+
+// search 以逐轮扩展的方式（每轮多一次转乘）找出各转乘次数下最早到达的行程
+func (p *Planner) search(origin, destination string, departAfter time.Time, opts Options) []Itinerary {
+	best := map[string]time.Time{origin: departAfter}
+	marked := map[string]label{origin: {arrival: departAfter}}
+	var found []Itinerary
+
+	for round := 0; round <= opts.MaxTransfers && len(marked) > 0; round++ {
+		improved := make(map[string]label)
+
+		for stationID, from := range marked {
+			earliestBoard := from.arrival
+			if len(from.legs) > 0 {
+				earliestBoard = earliestBoard.Add(opts.MinTransfer)
+			}
+
+			for _, ref := range p.byStation[stationID] {
+				t := p.trips[ref.trip]
+				boarding := t.stops[ref.index]
+				if boarding.departure.Before(earliestBoard) {
+					continue
+				}
+				if len(from.legs) > 0 && from.legs[len(from.legs)-1].TrainNo == t.info.TrainNo {
+					continue
+				}
+
+				for _, alighting := range t.stops[ref.index+1:] {
+					if known, ok := best[alighting.stationID]; ok && !alighting.arrival.Before(known) {
+						continue
+					}
+					if target, ok := best[destination]; ok && !alighting.arrival.Before(target) {
+						continue
+					}
+
+					best[alighting.stationID] = alighting.arrival
+					legs := make([]Leg, len(from.legs), len(from.legs)+1)
+					copy(legs, from.legs)
+					legs = append(legs, Leg{
+						TrainNo:         t.info.TrainNo,
+						TrainType:       t.info.TrainTypeName.ZhTw,
+						TrainTypeCode:   t.info.TrainTypeCode,
+						FromStationID:   boarding.stationID,
+						FromStationName: boarding.stationName,
+						ToStationID:     alighting.stationID,
+						ToStationName:   alighting.stationName,
+						Departure:       boarding.departure,
+						Arrival:         alighting.arrival,
+					})
+					improved[alighting.stationID] = label{arrival: alighting.arrival, legs: legs}
+				}
+			}
+		}
+
+		if l, ok := improved[destination]; ok {
+			found = append(found, Itinerary{Legs: l.legs})
+			delete(improved, destination)
+		}
+		marked = improved
+	}
+
+	return found
+}
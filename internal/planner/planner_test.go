@@ -0,0 +1,147 @@
+package planner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"tg-rail-shouting/internal/tdx"
+)
+
+var day = time.Date(2024, 5, 6, 0, 0, 0, 0, tdx.Location)
+
+// train 以 "站:到站-离站" 的方式建立每日时刻表，例如 train("1", "A:-08:00", "B:08:30-")
+func train(trainNo string, stops ...string) tdx.DailyTrainTimetable {
+	tt := tdx.DailyTrainTimetable{TrainInfo: tdx.GeneralTrainInfo{TrainNo: trainNo}}
+	for i, s := range stops {
+		station, times, _ := strings.Cut(s, ":")
+		arrival, departure, _ := strings.Cut(times, "-")
+		if arrival == "" {
+			arrival = departure
+		}
+		if departure == "" {
+			departure = arrival
+		}
+		tt.StopTimes = append(tt.StopTimes, tdx.StopTime{
+			StopSequence:  i + 1,
+			StationID:     station,
+			StationName:   tdx.StationName{ZhTw: station},
+			ArrivalTime:   arrival,
+			DepartureTime: departure,
+		})
+	}
+	return tt
+}
+
+func at(clock string) time.Time {
+	t, _ := tdx.StopTimeOn(day, clock)
+	return t
+}
+
+// 测试用的路网：1 次与 2 次在 B 站转乘比直达的 3 次快；4 次在 B 站的转乘时间只有 2 分钟
+var timetables = []tdx.DailyTrainTimetable{
+	train("1", "A:-08:00", "B:08:30-08:31", "C:09:00-"),
+	train("2", "B:-08:40", "D:09:10-"),
+	train("3", "A:-08:10", "D:09:30-"),
+	train("4", "B:-08:32", "D:09:00-"),
+	train("5", "C:-23:50", "D:00:20-"),
+}
+
+// trains 返回每个行程依序乘坐的车次
+func trains(itineraries []Itinerary) [][]string {
+	result := [][]string{}
+	for _, it := range itineraries {
+		var nos []string
+		for _, leg := range it.Legs {
+			nos = append(nos, leg.TrainNo)
+		}
+		result = append(result, nos)
+	}
+	return result
+}
+
+func TestPlan(t *testing.T) {
+	p := New(day, timetables)
+
+	tests := []struct {
+		name        string
+		origin      string
+		destination string
+		departAfter string
+		opts        Options
+		want        [][]string
+	}{
+		{"transfer beats direct", "A", "D", "07:00", Options{MaxTransfers: 1, MinTransfer: 5 * time.Minute}, [][]string{{"1", "2"}, {"3"}}},
+		{"tight transfer allowed", "A", "D", "07:00", Options{MaxTransfers: 1}, [][]string{{"1", "4"}, {"3"}}},
+		{"no transfers", "A", "D", "07:00", Options{MaxTransfers: 0, MinTransfer: 5 * time.Minute}, [][]string{{"3"}}},
+		{"first train missed", "A", "D", "08:05", Options{MaxTransfers: 1, MinTransfer: 5 * time.Minute}, [][]string{{"3"}}},
+		{"max results", "A", "D", "07:00", Options{MaxTransfers: 1, MaxResults: 1}, [][]string{{"1", "4"}}},
+		{"no service", "D", "A", "07:00", Options{MaxTransfers: 2}, [][]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := trains(p.Plan(tt.origin, tt.destination, at(tt.departAfter), tt.opts))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanLegTimes(t *testing.T) {
+	p := New(day, timetables)
+	results := p.Plan("A", "D", at("07:00"), Options{MaxTransfers: 1, MinTransfer: 5 * time.Minute, MaxResults: 1})
+	if len(results) != 1 {
+		t.Fatalf("got %d itineraries, want 1", len(results))
+	}
+
+	it := results[0]
+	if !it.Departure().Equal(at("08:00")) || !it.Arrival().Equal(at("09:10")) {
+		t.Errorf("itinerary %v-%v, want 08:00-09:10", it.Departure(), it.Arrival())
+	}
+	if it.Transfers() != 1 || it.Duration() != 70*time.Minute {
+		t.Errorf("transfers %d duration %v, want 1 and 70m", it.Transfers(), it.Duration())
+	}
+	first := it.Legs[0]
+	if first.FromStationID != "A" || first.ToStationID != "B" || !first.Departure.Equal(at("08:00")) || !first.Arrival.Equal(at("08:30")) {
+		t.Errorf("unexpected first leg %+v", first)
+	}
+}
+
+func TestPlanAcrossMidnight(t *testing.T) {
+	p := New(day, timetables)
+	results := p.Plan("C", "D", at("23:00"), Options{})
+	if len(results) != 1 {
+		t.Fatalf("got %d itineraries, want 1", len(results))
+	}
+	want := at("00:20").AddDate(0, 0, 1)
+	if arrival := results[0].Arrival(); !arrival.Equal(want) {
+		t.Errorf("arrival %v, want %v", arrival, want)
+	}
+}
+
+func TestDirectLegs(t *testing.T) {
+	p := New(day, timetables)
+
+	tests := []struct {
+		origin, destination string
+		want                []string
+	}{
+		{"A", "D", []string{"3"}},
+		{"A", "C", []string{"1"}},
+		{"B", "D", []string{"4", "2"}},
+		{"C", "A", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, leg := range p.DirectLegs(tt.origin, tt.destination) {
+			got = append(got, leg.TrainNo)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DirectLegs(%s, %s) = %v, want %v", tt.origin, tt.destination, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package ratelimit 提供令牌桶限流器，供 TDX 客户端与限流测试工具共用
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter 是令牌桶限流器：每秒补充 rate 个令牌，最多累积 burst 个。
+// rate 为 0 表示不限制；nil 的 *Limiter 也视为不限制
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// New 建立限流器，初始时令牌是满的
+func New(rate float64, burst int) *Limiter {
+	l := &Limiter{}
+	l.SetRate(rate, burst)
+	return l
+}
+
+// SetRate 调整补充速率与容量，已累积的令牌不会超过新的容量
+func (l *Limiter) SetRate(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 初始或从不限制改为限制时令牌是满的；不限制期间不会扣令牌，之前的预订也不再算数
+	if l.last.IsZero() || l.rate == 0 {
+		l.tokens = float64(burst)
+	} else {
+		l.refill(time.Now())
+	}
+	l.rate = math.Max(rate, 0)
+	l.burst = burst
+	l.tokens = math.Min(l.tokens, float64(burst))
+	l.last = time.Now()
+}
+
+// Rate 返回当前的补充速率与容量
+func (l *Limiter) Rate() (float64, int) {
+	if l == nil {
+		return 0, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate, l.burst
+}
+
+// refill 按经过的时间补充令牌，调用方需持有锁
+func (l *Limiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = math.Min(float64(l.burst), l.tokens+elapsed*l.rate)
+	}
+	l.last = now
+}
+
+// Allow 有令牌时取走一个并返回 true，不等待
+func (l *Limiter) Allow() bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate == 0 {
+		return true
+	}
+	l.refill(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Delay 返回还要多久才有可用的令牌，不取走令牌
+func (l *Limiter) Delay() time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate == 0 {
+		return 0
+	}
+	l.refill(time.Now())
+	if l.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Wait 等待并取走一个令牌；ctx 在等到令牌前结束时归还令牌并返回 ctx 的错误
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	if l.rate == 0 {
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+	// 先预订令牌（可以为负数），排在后面的调用者会等待更久
+	l.refill(time.Now())
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens = math.Min(float64(l.burst), l.tokens+1)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,178 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNilLimiter(t *testing.T) {
+	var l *Limiter
+	if !l.Allow() {
+		t.Error("nil limiter should allow")
+	}
+	if d := l.Delay(); d != 0 {
+		t.Errorf("nil limiter delay = %v, want 0", d)
+	}
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("nil limiter wait: %v", err)
+	}
+	if rate, burst := l.Rate(); rate != 0 || burst != 0 {
+		t.Errorf("nil limiter rate = %v/%d, want 0/0", rate, burst)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("nil limiter wait on cancelled ctx = %v, want context.Canceled", err)
+	}
+}
+
+func TestUnlimited(t *testing.T) {
+	l := New(0, 1)
+	for i := 0; i < 100; i++ {
+		if !l.Allow() {
+			t.Fatalf("rate 0 should never limit, denied at %d", i)
+		}
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if d := l.Delay(); d != 0 {
+		t.Errorf("delay = %v, want 0", d)
+	}
+}
+
+func TestBurst(t *testing.T) {
+	l := New(1, 3)
+	for i := 0; i < 3; i++ {
+		if !l.Allow() {
+			t.Fatalf("request %d within burst was denied", i)
+		}
+	}
+	if l.Allow() {
+		t.Error("request beyond burst was allowed")
+	}
+	if d := l.Delay(); d <= 0 || d > time.Second {
+		t.Errorf("delay = %v, want (0, 1s]", d)
+	}
+}
+
+func TestSetRateBounds(t *testing.T) {
+	l := New(-1, 0)
+	if rate, burst := l.Rate(); rate != 0 || burst != 1 {
+		t.Errorf("rate = %v/%d, want 0/1", rate, burst)
+	}
+
+	l = New(1, 5)
+	l.SetRate(1, 2)
+	for i := 0; i < 2; i++ {
+		if !l.Allow() {
+			t.Fatalf("request %d within new burst was denied", i)
+		}
+	}
+	if l.Allow() {
+		t.Error("tokens should be capped at the new burst")
+	}
+}
+
+func TestWaitPacesRequests(t *testing.T) {
+	l := New(50, 1)
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	// 第一个请求使用初始令牌，之后每 20ms 一个
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("6 waits at 50/s took %v, want at least 100ms", elapsed)
+	}
+}
+
+func TestConcurrentWaitersQueue(t *testing.T) {
+	l := New(100, 1)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background()); err != nil {
+				t.Errorf("wait: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 预订让令牌变为负数，10 个调用者依序排在 0ms、10ms、…、90ms
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("10 concurrent waits at 100/s took %v, want at least 90ms", elapsed)
+	}
+}
+
+func TestWaitCancelRefundsToken(t *testing.T) {
+	l := New(10, 1)
+	if !l.Allow() {
+		t.Fatal("initial token missing")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait = %v, want context.DeadlineExceeded", err)
+	}
+
+	// 取消的预订已归还，下一个令牌应在约 100ms 后可用，而不是 200ms
+	if d := l.Delay(); d > 100*time.Millisecond {
+		t.Errorf("delay after cancelled wait = %v, want at most 100ms", d)
+	}
+}
+
+func TestWaitCancelledContext(t *testing.T) {
+	l := New(1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// 有令牌时不需要等待，即使 ctx 已结束也会取走
+	if err := l.Wait(ctx); err != nil {
+		t.Errorf("wait with token available = %v, want nil", err)
+	}
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait without token = %v, want context.Canceled", err)
+	}
+	// 归还后仍只欠一个令牌
+	if d := l.Delay(); d > time.Second {
+		t.Errorf("delay = %v, want at most 1s", d)
+	}
+}
+
+func TestSetRateWithPendingReservations(t *testing.T) {
+	l := New(1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 三个预订让令牌变为 -2
+	for i := 0; i < 3; i++ {
+		go l.Wait(ctx)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	// 提高速率不会清除欠下的令牌：新的请求仍要排在预订之后
+	l.SetRate(100, 1)
+	if d := l.Delay(); d < 10*time.Millisecond || d > 40*time.Millisecond {
+		t.Errorf("delay after raising rate = %v, want about 30ms", d)
+	}
+
+	// 改为不限制时立即放行，再改回限制时从满的令牌桶开始
+	l.SetRate(0, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("wait while unlimited: %v", err)
+	}
+	l.SetRate(1, 1)
+	if !l.Allow() {
+		t.Error("re-limiting should start with a full bucket")
+	}
+}
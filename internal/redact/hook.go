@@ -0,0 +1,24 @@
+package redact
+
+import "github.com/sirupsen/logrus"
+
+// Hook 是 logrus 的 hook，在输出前移除日志信息与字段中的密钥
+type Hook struct{}
+
+func (Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (Hook) Fire(entry *logrus.Entry) error {
+	entry.Message = String(entry.Message)
+
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = String(v)
+		case error:
+			entry.Data[key] = Error(v)
+		}
+	}
+	return nil
+}
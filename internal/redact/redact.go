@@ -0,0 +1,92 @@
+// Package redact 从日志与发送到聊天的错误信息中移除密钥
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholder 取代被移除的密钥
+const Placeholder = "[REDACTED]"
+
+// 太短的值（例如聊天 ID）替换后反而会误伤一般文字，不视为密钥
+const minSecretLength = 8
+
+var (
+	mu      sync.RWMutex
+	secrets []string
+
+	// 即使未注册也一律移除的格式：Telegram Bot API 网址中的 token 与 Bearer token
+	patterns = []struct {
+		re          *regexp.Regexp
+		replacement string
+	}{
+		{regexp.MustCompile(`/bot\d+:[A-Za-z0-9_-]+`), "/bot" + Placeholder},
+		{regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`), "${1}" + Placeholder},
+		{regexp.MustCompile(`("access_token"\s*:\s*")[^"]+`), "${1}" + Placeholder},
+	}
+)
+
+// Register 注册需要移除的密钥，空字符串与过短的值会被忽略
+func Register(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength || contains(secrets, value) {
+			continue
+		}
+		secrets = append(secrets, value)
+	}
+	// 先替换较长的密钥，避免其中包含较短的密钥时只替换一部分
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// String 移除字符串中已注册的密钥与已知格式的 token
+func String(s string) string {
+	mu.RLock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, Placeholder)
+	}
+	mu.RUnlock()
+
+	for _, p := range patterns {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// Error 返回信息已移除密钥的错误，仍可用 errors.Is/As 检查原本的错误
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{message: String(err.Error()), err: err}
+}
+
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Server 是服务内置的 HTTP 服务器，各功能通过 Handle 挂载路由
+type Server struct {
+	mux    *http.ServeMux
+	server *http.Server
+}
+
+func New(addr string) *Server {
+	mux := http.NewServeMux()
+
+	return &Server{
+		mux: mux,
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.mux.HandleFunc(pattern, handler)
+}
+
+// Start 在后台开始监听
+func (s *Server) Start() {
+	go func() {
+		logrus.WithField("addr", s.server.Addr).Info("HTTP server started")
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.WithError(err).Error("HTTP server failed")
+		}
+	}()
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"tg-rail-shouting/internal/tdx"
+)
+
+// 台铁以延误未满 5 分钟为准点
+const OnTimeThresholdMinutes = 5
+
+// Punctuality 是一组观测记录的准点统计
+type Punctuality struct {
+	Count         int     `json:"count"` // 有行驶的车次数（不含停驶）
+	Cancelled     int     `json:"cancelled"`
+	OnTimePercent float64 `json:"on_time_percent"`
+	AvgDelay      float64 `json:"avg_delay_minutes"`
+	P90Delay      int     `json:"p90_delay_minutes"`
+}
+
+// Summarize 以每笔观测当天的最大延误计算准点率、平均延误与 P90 延误
+func Summarize(observations []Observation) Punctuality {
+	var p Punctuality
+	var delays []int
+	total := 0
+	onTime := 0
+
+	for _, obs := range observations {
+		if obs.Cancelled() {
+			p.Cancelled++
+			continue
+		}
+		delay := obs.Delay()
+		delays = append(delays, delay)
+		total += delay
+		if delay < OnTimeThresholdMinutes {
+			onTime++
+		}
+	}
+
+	p.Count = len(delays)
+	if p.Count == 0 {
+		return p
+	}
+
+	p.OnTimePercent = float64(onTime) * 100 / float64(p.Count)
+	p.AvgDelay = float64(total) / float64(p.Count)
+	p.P90Delay = Percentile(delays, 0.9)
+	return p
+}
+
+// Percentile 以最近秩法计算百分位数
+func Percentile(values []int, q float64) int {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]int, len(values))
+	copy(sorted, values)
+	sort.Ints(sorted)
+
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// WeekdayStats 是按星期分组的准点统计
+type WeekdayStats struct {
+	Weekday     time.Weekday `json:"weekday"`
+	Punctuality Punctuality  `json:"punctuality"`
+}
+
+// ByWeekday 按星期一到星期日分组统计，没有记录的星期会被略过
+func ByWeekday(observations []Observation) []WeekdayStats {
+	groups := make(map[time.Weekday][]Observation)
+	for _, obs := range observations {
+		date, err := time.Parse("2006-01-02", obs.Date)
+		if err != nil {
+			continue
+		}
+		groups[date.Weekday()] = append(groups[date.Weekday()], obs)
+	}
+
+	var stats []WeekdayStats
+	for _, weekday := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		if group, ok := groups[weekday]; ok {
+			stats = append(stats, WeekdayStats{Weekday: weekday, Punctuality: Summarize(group)})
+		}
+	}
+	return stats
+}
+
+// TrainStats 是单一车次的准点统计
+type TrainStats struct {
+	TrainNo     string      `json:"train_no"`
+	TrainType   string      `json:"train_type"`
+	Punctuality Punctuality `json:"punctuality"`
+}
+
+// ByTrain 按车次分组统计，按车次号排序
+func ByTrain(observations []Observation) []TrainStats {
+	groups := make(map[string][]Observation)
+	var trainNos []string
+	for _, obs := range observations {
+		if _, ok := groups[obs.TrainNo]; !ok {
+			trainNos = append(trainNos, obs.TrainNo)
+		}
+		groups[obs.TrainNo] = append(groups[obs.TrainNo], obs)
+	}
+	sort.Strings(trainNos)
+
+	stats := make([]TrainStats, 0, len(trainNos))
+	for _, trainNo := range trainNos {
+		group := groups[trainNo]
+		stats = append(stats, TrainStats{
+			TrainNo:     trainNo,
+			TrainType:   group[len(group)-1].TrainType,
+			Punctuality: Summarize(group),
+		})
+	}
+	return stats
+}
+
+// HourStats 是某个表定时段（整点）的准点统计
+type HourStats struct {
+	Hour        int         `json:"hour"`
+	Punctuality Punctuality `json:"punctuality"`
+}
+
+// ByHour 按表定时间的小时分组统计，按小时排序
+func ByHour(observations []Observation) []HourStats {
+	groups := make(map[int][]Observation)
+	for _, obs := range observations {
+		// 即时看板的时间为 HH:MM:SS，时刻表为 HH:MM
+		clock, ok := tdx.StopTimeOn(time.Time{}, obs.ScheduledTime)
+		if !ok {
+			continue
+		}
+		groups[clock.Hour()] = append(groups[clock.Hour()], obs)
+	}
+
+	var stats []HourStats
+	for hour := 0; hour < 24; hour++ {
+		if group, ok := groups[hour]; ok {
+			stats = append(stats, HourStats{Hour: hour, Punctuality: Summarize(group)})
+		}
+	}
+	return stats
+}
+
+// ArrivalProbability 返回延误不超过 slackMinutes 的天数比例（停驶视为未能准时）及天数。
+// 同一天有多笔记录时以延误最大的一笔为准，避免同一车次被多个监控配置档记录时重复计算
+func ArrivalProbability(observations []Observation, slackMinutes int) (float64, int) {
+	worst := make(map[string]Observation)
+	for _, obs := range observations {
+		previous, ok := worst[obs.Date]
+		if !ok || obs.Cancelled() || (!previous.Cancelled() && obs.Delay() > previous.Delay()) {
+			worst[obs.Date] = obs
+		}
+	}
+	if len(worst) == 0 {
+		return 0, 0
+	}
+
+	ok := 0
+	for _, obs := range worst {
+		if !obs.Cancelled() && obs.Delay() <= slackMinutes {
+			ok++
+		}
+	}
+	return float64(ok) / float64(len(worst)), len(worst)
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestByHour(t *testing.T) {
+	observations := []Observation{
+		{ScheduledTime: "18:05:00", DelayMinutes: 0},  // 即时看板的 HH:MM:SS
+		{ScheduledTime: "18:40:00", DelayMinutes: 10}, // 同一小时
+		{ScheduledTime: "19:10", DelayMinutes: 2},     // 时刻表的 HH:MM
+		{ScheduledTime: "", DelayMinutes: 3},          // 无法解析的时间被略过
+		{ScheduledTime: "bad", DelayMinutes: 3},
+	}
+
+	stats := ByHour(observations)
+
+	var hours []int
+	counts := make(map[int]int)
+	for _, s := range stats {
+		hours = append(hours, s.Hour)
+		counts[s.Hour] = s.Punctuality.Count
+	}
+	if want := []int{18, 19}; !reflect.DeepEqual(hours, want) {
+		t.Fatalf("hours = %v, want %v", hours, want)
+	}
+	if counts[18] != 2 || counts[19] != 1 {
+		t.Errorf("counts = %v, want 18:2 19:1", counts)
+	}
+	if got := stats[0].Punctuality.OnTimePercent; got != 50 {
+		t.Errorf("18:00 on-time = %v%%, want 50%%", got)
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	observations := []Observation{
+		{Date: "2024-05-06", TrainNo: "1", StationID: "1180", Watch: "a", DelayMinutes: 2},
+		{Date: "2024-05-06", TrainNo: "1", StationID: "1180", Watch: "b", DelayMinutes: 5},
+		{Date: "2024-05-06", TrainNo: "1", StationID: "1000", Watch: "c", DelayMinutes: 1},
+		{Date: "2024-05-07", TrainNo: "1", StationID: "1180", Watch: "a", DelayMinutes: 0},
+		{Date: "2024-05-07", TrainNo: "1", StationID: "1180", Watch: "b", RunningStatus: 2},
+	}
+
+	distinct := Distinct(observations)
+	if len(distinct) != 3 {
+		t.Fatalf("got %d observations, want 3", len(distinct))
+	}
+	if distinct[0].Watch != "b" || distinct[0].Delay() != 5 {
+		t.Errorf("kept %+v, want the larger delay from watch b", distinct[0])
+	}
+	if !distinct[2].Cancelled() {
+		t.Errorf("kept %+v, want the cancelled record", distinct[2])
+	}
+}
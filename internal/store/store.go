@@ -0,0 +1,167 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var observationsBucket = []byte("observations")
+
+// Observation 是监控配置档看到的某车次某天在某站的状况，同一配置档同一天多次观测时以最后一次为准
+type Observation struct {
+	Date            string    `json:"date"` // YYYY-MM-DD（台湾时间）
+	Operator        string    `json:"operator"`
+	TrainNo         string    `json:"train_no"`
+	TrainType       string    `json:"train_type"`
+	StationID       string    `json:"station_id"`
+	Watch           string    `json:"watch"`
+	ScheduledTime   string    `json:"scheduled_time"` // 表定到站（无则为离站）时间 HH:MM:SS 或 HH:MM
+	DelayMinutes    int       `json:"delay_minutes"`
+	MaxDelayMinutes int       `json:"max_delay_minutes"`
+	Platform        string    `json:"platform,omitempty"`
+	RunningStatus   int       `json:"running_status"` // 0:准点 1:晚点 2:取消
+	ObservedAt      time.Time `json:"observed_at"`
+}
+
+// Delay 返回统计用的延误分钟数：当天观测到的最大延误。
+// 列车过站后会从看板消失，最后一次观测的延误未必是最大值；旧记录没有最大延误时使用最后一次的延误
+func (o Observation) Delay() int {
+	if o.MaxDelayMinutes > o.DelayMinutes {
+		return o.MaxDelayMinutes
+	}
+	return o.DelayMinutes
+}
+
+// Cancelled 判断车次是否停驶
+func (o Observation) Cancelled() bool {
+	return o.RunningStatus == 2
+}
+
+func (o Observation) key() []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s|%s|%s", o.Date, o.Operator, o.TrainNo, o.StationID, o.Watch))
+}
+
+// Distinct 合并不同监控配置档对同一车次、同一天、同一站的记录，只保留延误最大的一笔，
+// 供不按监控配置档区分的统计使用，避免重复计算
+func Distinct(observations []Observation) []Observation {
+	index := make(map[string]int)
+	var distinct []Observation
+	for _, obs := range observations {
+		key := fmt.Sprintf("%s|%s|%s|%s", obs.Date, obs.Operator, obs.TrainNo, obs.StationID)
+		i, ok := index[key]
+		switch {
+		case !ok:
+			index[key] = len(distinct)
+			distinct = append(distinct, obs)
+		case obs.Cancelled() || (!distinct[i].Cancelled() && obs.Delay() > distinct[i].Delay()):
+			distinct[i] = obs
+		}
+	}
+	return distinct
+}
+
+// Store 是保存观测记录的嵌入式数据库
+type Store struct {
+	db *bolt.DB
+}
+
+// Open 打开（必要时创建）数据库文件
+func Open(path string) (*Store, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create store directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(observationsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Record 保存观测记录，同一配置档同一车次同一天同一站的记录会被更新，并保留当天的最大延误
+func (s *Store) Record(observations []Observation) error {
+	if len(observations) == 0 {
+		return nil
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(observationsBucket)
+		for _, obs := range observations {
+			key := obs.key()
+
+			obs.MaxDelayMinutes = obs.DelayMinutes
+			if existing := bucket.Get(key); existing != nil {
+				var previous Observation
+				if err := json.Unmarshal(existing, &previous); err == nil && previous.MaxDelayMinutes > obs.MaxDelayMinutes {
+					obs.MaxDelayMinutes = previous.MaxDelayMinutes
+				}
+			}
+
+			value, err := json.Marshal(obs)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(key, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query 限定查询的范围，空字段表示不限
+type Query struct {
+	Since     time.Time
+	TrainNo   string
+	StationID string
+	Watch     string
+}
+
+// Observations 返回自 Since 当天起符合条件的观测记录，按日期排序
+func (s *Store) Observations(q Query) ([]Observation, error) {
+	var observations []Observation
+	since := []byte(q.Since.Format("2006-01-02"))
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(observationsBucket).Cursor()
+		for k, v := cursor.Seek(since); k != nil; k, v = cursor.Next() {
+			var obs Observation
+			if err := json.Unmarshal(v, &obs); err != nil {
+				return fmt.Errorf("failed to decode observation %s: %w", k, err)
+			}
+			if q.TrainNo != "" && obs.TrainNo != q.TrainNo {
+				continue
+			}
+			if q.StationID != "" && obs.StationID != q.StationID {
+				continue
+			}
+			if q.Watch != "" && obs.Watch != q.Watch {
+				continue
+			}
+			observations = append(observations, obs)
+		}
+		return nil
+	})
+
+	return observations, err
+}
@@ -0,0 +1,45 @@
+package tdx
+
+import (
+	"context"
+	"time"
+)
+
+// applyAmenities 从车次基本资料带入自行车、无障碍、餐车等服务标记
+func (t *TrainInfo) applyAmenities(info GeneralTrainInfo) {
+	t.Bike = info.BikeFlag == 1
+	t.Wheelchair = info.WheelchairFlag == 1
+	t.Dining = info.DiningFlag == 1
+	if t.TrainTypeCode == "" {
+		t.TrainTypeCode = info.TrainTypeCode
+	}
+}
+
+// AnnotateAmenities 即时看板不含服务标记，用当天的每日时刻表补上
+func (c *Client) AnnotateAmenities(op Operator, trains []TrainInfo) error {
+	return c.AnnotateAmenitiesContext(context.Background(), op, trains)
+}
+
+// AnnotateAmenitiesContext 与 AnnotateAmenities 相同，ctx 用于取消请求
+func (c *Client) AnnotateAmenitiesContext(ctx context.Context, op Operator, trains []TrainInfo) error {
+	if op != OperatorTRA || len(trains) == 0 {
+		return nil
+	}
+
+	timetables, err := c.GetDailyTimetableContext(ctx, op, time.Now())
+	if err != nil {
+		return err
+	}
+
+	infoByTrainNo := make(map[string]GeneralTrainInfo, len(timetables))
+	for _, tt := range timetables {
+		infoByTrainNo[tt.TrainInfo.TrainNo] = tt.TrainInfo
+	}
+
+	for i := range trains {
+		if info, ok := infoByTrainNo[trains[i].TrainNo]; ok {
+			trains[i].applyAmenities(info)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package tdx
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"tg-rail-shouting/internal/metrics"
+)
+
+// cache 是一个简单的带过期时间的内存缓存，用于很少变动的数据（票价、车站列表等）
+type cache struct {
+	mu    sync.Mutex
+	items map[string]cacheItem
+}
+
+type cacheItem struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newCache() *cache {
+	return &cache{items: make(map[string]cacheItem)}
+}
+
+func (c *cache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// 缓存名称取 key 的第一段，例如 "odfare:1180:1190" 为 odfare
+	name := strings.SplitN(key, ":", 2)[0]
+
+	item, ok := c.items[key]
+	if ok && time.Now().After(item.expiresAt) {
+		delete(c.items, key)
+		ok = false
+	}
+	if !ok {
+		metrics.TDXCacheLookups.WithLabelValues(name, "miss").Inc()
+		return nil, false
+	}
+	metrics.TDXCacheLookups.WithLabelValues(name, "hit").Inc()
+	return item.value, true
+}
+
+func (c *cache) set(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = cacheItem{value: value, expiresAt: time.Now().Add(ttl)}
+}
@@ -1,119 +1,184 @@
 package tdx
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/health"
+	"tg-rail-shouting/internal/httpclient"
+	"tg-rail-shouting/internal/metrics"
+	"tg-rail-shouting/internal/redact"
 )
 
+// DefaultRequestTimeout 是调用方没有设置期限时，每个 TDX 请求（不含等待限流）的超时时间
+const DefaultRequestTimeout = 30 * time.Second
+
 type Client struct {
 	client       *resty.Client
 	clientID     string
 	clientSecret string
 	baseURL      string
 	authURL      string
-	accessToken  string
-	tokenExpiry  time.Time
+	tokens       tokenState
+	cache        *cache
+	baseURLs     map[Operator]string
+	quota        *quota
+	limiters     *limiters
+	timeout      time.Duration
 }
 
 func NewClient(clientID, clientSecret, baseURL, authURL string) *Client {
-	client := resty.New()
-	client.SetHeader("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-	
 	return &Client{
-		client:       client,
+		client:       httpclient.Default(),
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		baseURL:      baseURL,
 		authURL:      authURL,
+		cache:        newCache(),
+		baseURLs:     make(map[Operator]string),
+		quota:        &quota{},
+		limiters:     newLimiters(),
+		timeout:      DefaultRequestTimeout,
 	}
 }
 
-func (c *Client) authenticate() error {
-	// 如果没有提供认证信息，使用免费API
-	if c.clientID == "" || c.clientSecret == "" {
-		logrus.Info("Using TDX API without authentication (free tier)")
-		return nil
-	}
-	
-	if time.Now().Before(c.tokenExpiry) {
-		return nil
+// getJSON 经过限流后发送带认证的 GET 请求，并将 JSON 响应解析到 out；
+// ctx 结束时停止等待限流并取消请求
+func (c *Client) getJSON(ctx context.Context, url string, query *Query, out interface{}) error {
+	err := c.doGetJSON(ctx, url, query, out)
+	// 调用方取消的请求不计入健康状态
+	if ctx.Err() == nil {
+		health.RecordTDXFetch(err)
 	}
+	return err
+}
 
-	logrus.Info("Authenticating with TDX API...")
-	
-	resp, err := c.client.R().
-		SetHeader("Content-Type", "application/x-www-form-urlencoded").
-		SetFormData(map[string]string{
-			"grant_type":    "client_credentials",
-			"client_id":     c.clientID,
-			"client_secret": c.clientSecret,
-		}).
-		Post(c.authURL)
-
+func (c *Client) doGetJSON(ctx context.Context, url string, query *Query, out interface{}) error {
+	token, err := c.accessToken(ctx)
 	if err != nil {
-		logrus.WithError(err).Warn("Authentication request failed, falling back to free API")
-		c.accessToken = ""
-		return nil
+		return err
 	}
 
-	if resp.StatusCode() != 200 {
-		logrus.WithFields(logrus.Fields{
-			"status": resp.StatusCode(),
-			"body":   resp.String(),
-		}).Warn("Authentication failed, falling back to free API")
-		c.accessToken = ""
-		return nil
-	}
+	err = c.get(ctx, url, query, token, out)
 
-	var tokenResp TokenResponse
-	if err := json.Unmarshal(resp.Body(), &tokenResp); err != nil {
-		logrus.WithError(err).Warn("Failed to parse token response, falling back to free API")
-		c.accessToken = ""
-		return nil
+	// token 可能在过期前就被 TDX 拒绝：重新认证后重试一次
+	var apiErr *APIError
+	if token != "" && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		logrus.Warn("TDX rejected the access token, re-authenticating")
+		c.invalidateToken(token)
+		if token, err = c.accessToken(ctx); err != nil {
+			return err
+		}
+		err = c.get(ctx, url, query, token, out)
 	}
-
-	c.accessToken = tokenResp.AccessToken
-	c.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn-60) * time.Second)
-	
-	logrus.Info("TDX API authentication successful")
-	return nil
+	return err
 }
 
-func (c *Client) GetStationInfo(stationID string) (*Station, error) {
-	if err := c.authenticate(); err != nil {
-		return nil, err
+// get 发送一次 GET 请求，token 为空时使用免费 API
+func (c *Client) get(ctx context.Context, url string, query *Query, token string, out interface{}) error {
+	params, err := query.Params()
+	if err != nil {
+		return err
+	}
+	if err := c.waitRateLimit(ctx, tierFor(token)); err != nil {
+		return err
 	}
 
-	url := fmt.Sprintf("%s/Rail/TRA/Station", c.baseURL)
-	filter := fmt.Sprintf("StationID eq '%s'", stationID)
+	reqCtx, cancel := withTimeout(ctx, c.timeout)
+	defer cancel()
 
 	req := c.client.R().
-		SetQueryParam("$filter", filter).
-		SetQueryParam("$format", "JSON")
-	
-	if c.accessToken != "" {
-		req.SetHeader("Authorization", "Bearer "+c.accessToken)
+		SetContext(reqCtx).
+		SetQueryParams(params)
+
+	if token != "" {
+		req.SetHeader("Authorization", "Bearer "+token)
 	}
-	
+
+	endpoint := endpointLabel(url)
+	start := time.Now()
 	resp, err := req.Get(url)
+	metrics.TDXRequestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+	c.quota.record()
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get station info: %w", err)
+		metrics.TDXRequests.WithLabelValues(endpoint, "error").Inc()
+		return err
 	}
+	metrics.TDXRequests.WithLabelValues(endpoint, strconv.Itoa(resp.StatusCode())).Inc()
 
 	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("API request failed with status: %d, body: %s", resp.StatusCode(), resp.String())
+		return &APIError{
+			StatusCode: resp.StatusCode(),
+			Body:       truncate(redact.String(resp.String()), 500),
+			RetryAfter: parseRetryAfter(resp.Header().Get("Retry-After")),
+		}
+	}
+
+	if err := json.Unmarshal(resp.Body(), out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	return nil
+}
+
+// SetTransport 按 opts 设置代理、CA、超时与 User-Agent，需在发出请求前调用
+func (c *Client) SetTransport(opts httpclient.Options) error {
+	client, err := httpclient.New(opts)
+	if err != nil {
+		return err
+	}
+	c.client = client
+	if opts.ReadTimeout > 0 {
+		c.timeout = opts.ReadTimeout
+	}
+	return nil
+}
+
+// withTimeout 在调用方没有设置期限时套用默认的请求超时
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// endpointLabel 从请求 URL 取出低基数的接口名称，例如 "TRA/ODFare"
+func endpointLabel(url string) string {
+	idx := strings.Index(url, "/Rail/")
+	if idx == -1 {
+		return "unknown"
+	}
+	parts := strings.SplitN(url[idx+len("/Rail/"):], "/", 3)
+	if len(parts) < 2 {
+		return parts[0]
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+func (c *Client) GetStationInfo(stationID string) (*Station, error) {
+	return c.GetStationInfoContext(context.Background(), stationID)
+}
+
+// GetStationInfoContext 与 GetStationInfo 相同，ctx 用于取消请求
+func (c *Client) GetStationInfoContext(ctx context.Context, stationID string) (*Station, error) {
+	url := fmt.Sprintf("%s/Rail/TRA/Station", c.baseURL)
+	query := NewQuery().
+		Filter(EqString("StationID", stationID)).
+		Select(stationFields...)
+
 	var stations []Station
-	if err := json.Unmarshal(resp.Body(), &stations); err != nil {
-		return nil, fmt.Errorf("failed to parse station response: %w", err)
+	if err := c.getJSON(ctx, url, query, &stations); err != nil {
+		return nil, fmt.Errorf("failed to get station info: %w", err)
 	}
 
 	if len(stations) == 0 {
@@ -125,35 +190,21 @@ func (c *Client) GetStationInfo(stationID string) (*Station, error) {
 
 // GetTrainTimetable 获取车站的实时列车信息
 func (c *Client) GetTrainTimetable(stationID string, direction int) ([]TrainInfo, error) {
-	if err := c.authenticate(); err != nil {
-		return nil, err
-	}
+	return c.GetTrainTimetableContext(context.Background(), stationID, direction)
+}
 
+// GetTrainTimetableContext 与 GetTrainTimetable 相同，ctx 用于取消请求
+func (c *Client) GetTrainTimetableContext(ctx context.Context, stationID string, direction int) ([]TrainInfo, error) {
 	// 使用StationLiveBoard获取实时信息
 	url := fmt.Sprintf("%s/Rail/TRA/StationLiveBoard", c.baseURL)
-	filter := fmt.Sprintf("StationID eq '%s'", stationID)
-	
-	req := c.client.R().
-		SetQueryParam("$format", "JSON").
-		SetQueryParam("$filter", filter)
-	
-	if c.accessToken != "" {
-		req.SetHeader("Authorization", "Bearer "+c.accessToken)
-	}
-	
-	resp, err := req.Get(url)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get station live board: %w", err)
-	}
-
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("API request failed with status: %d, body: %s", resp.StatusCode(), resp.String())
-	}
+	query := NewQuery().
+		Filter(And(EqString("StationID", stationID), EqInt("Direction", direction))).
+		Select("StationID", "TrainNo", "Direction", "TrainTypeCode", "TrainTypeName", "EndingStationName",
+			"Platform", "ScheduleArrivalTime", "ScheduleDepartureTime", "DelayTime", "RunningStatus")
 
 	var liveBoard StationLiveBoardResponse
-	if err := json.Unmarshal(resp.Body(), &liveBoard); err != nil {
-		return nil, fmt.Errorf("failed to parse live board response: %w", err)
+	if err := c.getJSON(ctx, url, query, &liveBoard); err != nil {
+		return nil, fmt.Errorf("failed to get station live board: %w", err)
 	}
 
 	var trains []TrainInfo
@@ -170,12 +221,17 @@ func (c *Client) GetTrainTimetable(stationID string, direction int) ([]TrainInfo
 			// 只顯示當前時間之後的列車（還沒過站的）
 			if arrivalTime >= currentTime {
 				trainInfo := TrainInfo{
+					Operator:      OperatorTRA,
 					TrainNo:       board.TrainNo,
 					TrainType:     board.TrainTypeName.ZhTw,
+					TrainTypeCode: board.TrainTypeCode,
 					ArrivalTime:   arrivalTime,
 					DepartureTime: board.ScheduleDepartureTime,
 					Direction:     board.Direction,
 					EndStation:    board.EndingStationName.ZhTw,
+					Platform:      board.Platform,
+					DelayMinutes:  board.DelayTime,
+					RunningStatus: board.RunningStatus,
 				}
 
 				trains = append(trains, trainInfo)
@@ -193,33 +249,20 @@ func (c *Client) GetTrainTimetable(stationID string, direction int) ([]TrainInfo
 
 // GetGeneralTimetable 获取完整的时刻表数据（备用方法）
 func (c *Client) GetGeneralTimetable(stationID string, direction int) ([]TrainInfo, error) {
-	if err := c.authenticate(); err != nil {
-		return nil, err
-	}
+	return c.GetGeneralTimetableContext(context.Background(), stationID, direction)
+}
 
+// GetGeneralTimetableContext 与 GetGeneralTimetable 相同，ctx 用于取消请求
+func (c *Client) GetGeneralTimetableContext(ctx context.Context, stationID string, direction int) ([]TrainInfo, error) {
 	url := fmt.Sprintf("%s/Rail/TRA/GeneralTimetable", c.baseURL)
-	
-	req := c.client.R().
-		SetQueryParam("$format", "JSON").
-		SetQueryParam("$top", "100")
-	
-	if c.accessToken != "" {
-		req.SetHeader("Authorization", "Bearer "+c.accessToken)
-	}
-	
-	resp, err := req.Get(url)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get general timetable: %w", err)
-	}
-
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("API request failed with status: %d, body: %s", resp.StatusCode(), resp.String())
-	}
 
 	var timetables []GeneralTimetableData
-	if err := json.Unmarshal(resp.Body(), &timetables); err != nil {
-		return nil, fmt.Errorf("failed to parse timetable response: %w", err)
+	query := NewQuery().
+		Filter(EqInt("GeneralTimetable/GeneralTrainInfo/Direction", direction)).
+		Select("GeneralTimetable").
+		Top(100)
+	if err := c.getJSON(ctx, url, query, &timetables); err != nil {
+		return nil, fmt.Errorf("failed to get general timetable: %w", err)
 	}
 
 	var trains []TrainInfo
@@ -240,14 +283,17 @@ func (c *Client) GetGeneralTimetable(stationID string, direction int) ([]TrainIn
 
 				if arrivalTime >= currentTime {
 					trainInfo := TrainInfo{
+						Operator:      OperatorTRA,
 						TrainNo:       tt.GeneralTimetable.GeneralTrainInfo.TrainNo,
 						TrainType:     tt.GeneralTimetable.GeneralTrainInfo.TrainTypeName.ZhTw,
+						TrainTypeCode: tt.GeneralTimetable.GeneralTrainInfo.TrainTypeCode,
 						ArrivalTime:   arrivalTime,
 						DepartureTime: st.DepartureTime,
 						StopSequence:  st.StopSequence,
 						Direction:     tt.GeneralTimetable.GeneralTrainInfo.Direction,
 						EndStation:    tt.GeneralTimetable.GeneralTrainInfo.EndingStationName.ZhTw,
 					}
+					trainInfo.applyAmenities(tt.GeneralTimetable.GeneralTrainInfo)
 
 					stations := c.extractStationInfo(tt.GeneralTimetable.StopTimes, st.StopSequence)
 					trainInfo.Stations = stations
@@ -285,34 +331,21 @@ func (c *Client) extractStationInfo(stopTimes []StopTime, currentSequence int) [
 }
 
 func (c *Client) GetTrainRoute(trainNo string) ([]StationInfo, error) {
-	if err := c.authenticate(); err != nil {
-		return nil, err
-	}
+	return c.GetTrainRouteContext(context.Background(), trainNo)
+}
 
+// GetTrainRouteContext 与 GetTrainRoute 相同，ctx 用于取消请求
+func (c *Client) GetTrainRouteContext(ctx context.Context, trainNo string) ([]StationInfo, error) {
 	url := fmt.Sprintf("%s/Rail/TRA/GeneralTimetable", c.baseURL)
-	filter := fmt.Sprintf("GeneralTimetable/GeneralTrainInfo/TrainNo eq '%s'", trainNo)
-	
-	req := c.client.R().
-		SetQueryParam("$format", "JSON").
-		SetQueryParam("$filter", filter)
-	
-	if c.accessToken != "" {
-		req.SetHeader("Authorization", "Bearer "+c.accessToken)
-	}
-	
-	resp, err := req.Get(url)
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get train route: %w", err)
-	}
-
-	if resp.StatusCode() != 200 {
-		return nil, fmt.Errorf("API request failed with status: %d, body: %s", resp.StatusCode(), resp.String())
-	}
+	// 只用得到第一笔
+	query := NewQuery().
+		Filter(EqString("GeneralTimetable/GeneralTrainInfo/TrainNo", trainNo)).
+		Select("GeneralTimetable").
+		Top(1)
 
 	var timetables []GeneralTimetableData
-	if err := json.Unmarshal(resp.Body(), &timetables); err != nil {
-		return nil, fmt.Errorf("failed to parse route response: %w", err)
+	if err := c.getJSON(ctx, url, query, &timetables); err != nil {
+		return nil, fmt.Errorf("failed to get train route: %w", err)
 	}
 
 	if len(timetables) == 0 {
@@ -334,7 +367,12 @@ func (c *Client) GetTrainRoute(trainNo string) ([]StationInfo, error) {
 }
 
 func (c *Client) FindRouteToFugang(trainNo string, fromStationID string) ([]StationInfo, bool, error) {
-	route, err := c.GetTrainRoute(trainNo)
+	return c.FindRouteToFugangContext(context.Background(), trainNo, fromStationID)
+}
+
+// FindRouteToFugangContext 与 FindRouteToFugang 相同，ctx 用于取消请求
+func (c *Client) FindRouteToFugangContext(ctx context.Context, trainNo string, fromStationID string) ([]StationInfo, bool, error) {
+	route, err := c.GetTrainRouteContext(ctx, trainNo)
 	if err != nil {
 		return nil, false, err
 	}
@@ -363,4 +401,12 @@ func (c *Client) FindRouteToFugang(trainNo string, fromStationID string) ([]Stat
 	}
 
 	return route[fromIndex:], false, nil
-}
\ No newline at end of file
+}
+// truncate 截断过长的响应内容，避免日志与错误信息过长
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "..."
+}
@@ -0,0 +1,143 @@
+package tdx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 票价很少变动，缓存一天即可
+const fareCacheTTL = 24 * time.Hour
+
+// 票价表使用的车种分类
+const (
+	FareTrainTypeTzeChiang = 1 // 自強（含太魯閣、普悠瑪、EMU3000）
+	FareTrainTypeChuKuang  = 2 // 莒光
+	FareTrainTypeFuHsing   = 3 // 復興、區間、區間快
+	FareTrainTypeOrdinary  = 4 // 普快
+)
+
+// 票种与身份：全票单程 / 成人
+const (
+	TicketTypeSingle = 1
+	FareClassAdult   = 1
+)
+
+// fareTrainTypeByCode 将时刻表中的 TrainTypeCode 映射到票价车种
+var fareTrainTypeByCode = map[string]int{
+	"1":  FareTrainTypeTzeChiang, // 太魯閣
+	"2":  FareTrainTypeTzeChiang, // 普悠瑪
+	"3":  FareTrainTypeTzeChiang, // 自強
+	"11": FareTrainTypeTzeChiang, // 自強(3000)
+	"4":  FareTrainTypeChuKuang,  // 莒光
+	"5":  FareTrainTypeFuHsing,   // 復興
+	"6":  FareTrainTypeFuHsing,   // 區間
+	"10": FareTrainTypeFuHsing,   // 區間快
+	"7":  FareTrainTypeOrdinary,  // 普快
+}
+
+// FareTable 是一个起讫站之间各车种的票价
+type FareTable struct {
+	OriginStationID      string
+	OriginStationName    string
+	DestinationStationID string
+	DestinationName      string
+	Fares                []ODFare
+}
+
+// AdultFare 返回指定车种(TrainTypeCode)的成人全票票价，找不到时返回 0
+func (t *FareTable) AdultFare(trainTypeCode string) int {
+	fareType, ok := fareTrainTypeByCode[trainTypeCode]
+	if !ok {
+		return 0
+	}
+	for _, od := range t.Fares {
+		if od.TrainType != fareType {
+			continue
+		}
+		for _, fare := range od.Fares {
+			if fare.TicketType == TicketTypeSingle && fare.FareClass == FareClassAdult {
+				return fare.Price
+			}
+		}
+	}
+	return 0
+}
+
+// AdultFares 返回每个票价车种的成人全票票价
+func (t *FareTable) AdultFares() map[int]int {
+	prices := make(map[int]int)
+	for _, od := range t.Fares {
+		for _, fare := range od.Fares {
+			if fare.TicketType == TicketTypeSingle && fare.FareClass == FareClassAdult {
+				prices[od.TrainType] = fare.Price
+				break
+			}
+		}
+	}
+	return prices
+}
+
+// FareTrainTypeName 返回票价车种的中文名称
+func FareTrainTypeName(trainType int) string {
+	switch trainType {
+	case FareTrainTypeTzeChiang:
+		return "自強"
+	case FareTrainTypeChuKuang:
+		return "莒光"
+	case FareTrainTypeFuHsing:
+		return "復興/區間"
+	case FareTrainTypeOrdinary:
+		return "普快"
+	default:
+		return fmt.Sprintf("車種%d", trainType)
+	}
+}
+
+// SortedFareTrainTypes 返回票价表中出现的车种，按编号排序
+func SortedFareTrainTypes(prices map[int]int) []int {
+	types := make([]int, 0, len(prices))
+	for t := range prices {
+		types = append(types, t)
+	}
+	sort.Ints(types)
+	return types
+}
+
+// GetODFare 获取两站之间的票价（带缓存）
+func (c *Client) GetODFare(originStationID, destinationStationID string) (*FareTable, error) {
+	return c.GetODFareContext(context.Background(), originStationID, destinationStationID)
+}
+
+// GetODFareContext 与 GetODFare 相同，ctx 用于取消请求
+func (c *Client) GetODFareContext(ctx context.Context, originStationID, destinationStationID string) (*FareTable, error) {
+	cacheKey := fmt.Sprintf("odfare:%s:%s", originStationID, destinationStationID)
+	if cached, ok := c.cache.get(cacheKey); ok {
+		return cached.(*FareTable), nil
+	}
+
+	url := fmt.Sprintf("%s/Rail/TRA/ODFare/%s/to/%s", c.baseURL, originStationID, destinationStationID)
+
+	query := NewQuery().Select("OriginStationName", "DestinationStationName", "Direction", "TrainType", "Fares")
+
+	var resp ODFareResponse
+	if err := c.getJSON(ctx, url, query, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get OD fare: %w", err)
+	}
+
+	if len(resp.ODFares) == 0 {
+		return nil, fmt.Errorf("fare not found: %s -> %s", originStationID, destinationStationID)
+	}
+
+	table := &FareTable{
+		OriginStationID:      originStationID,
+		OriginStationName:    resp.ODFares[0].OriginStationName.ZhTw,
+		DestinationStationID: destinationStationID,
+		DestinationName:      resp.ODFares[0].DestinationStationName.ZhTw,
+		Fares:                resp.ODFares,
+	}
+
+	c.cache.set(cacheKey, table, fareCacheTTL)
+	return table, nil
+}
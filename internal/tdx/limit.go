@@ -0,0 +1,109 @@
+package tdx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tg-rail-shouting/internal/metrics"
+	"tg-rail-shouting/internal/ratelimit"
+)
+
+// Tier 是 TDX 的请求等级，未认证与认证后的限流各自独立计算
+type Tier string
+
+const (
+	TierAnonymous     Tier = "anonymous"
+	TierAuthenticated Tier = "authenticated"
+)
+
+// 默认限流：认证后每秒 5 次，未认证每秒 2 次，
+// 避免看板之后连续查询路线时触发 TDX 的每秒限制
+const (
+	DefaultRateLimit          = 5
+	DefaultRateBurst          = 5
+	DefaultAnonymousRateLimit = 2
+	DefaultAnonymousRateBurst = 2
+)
+
+// limiters 按请求等级分开的限流器
+type limiters struct {
+	anonymous     *ratelimit.Limiter
+	authenticated *ratelimit.Limiter
+}
+
+func newLimiters() *limiters {
+	return &limiters{
+		anonymous:     ratelimit.New(DefaultAnonymousRateLimit, DefaultAnonymousRateBurst),
+		authenticated: ratelimit.New(DefaultRateLimit, DefaultRateBurst),
+	}
+}
+
+func (l *limiters) get(tier Tier) *ratelimit.Limiter {
+	if tier == TierAuthenticated {
+		return l.authenticated
+	}
+	return l.anonymous
+}
+
+// SetRateLimit 设置指定等级每秒的请求数与可连续发出的请求数，rate 为 0 表示不限制
+func (c *Client) SetRateLimit(tier Tier, rate float64, burst int) {
+	c.limiters.get(tier).SetRate(rate, burst)
+}
+
+// RateLimiter 返回指定等级的请求共用的限流器
+func (c *Client) RateLimiter(tier Tier) *ratelimit.Limiter {
+	return c.limiters.get(tier)
+}
+
+// tierFor 返回使用 token 的请求所属的等级：带 access token 的请求按认证等级限流
+func tierFor(token string) Tier {
+	if token != "" {
+		return TierAuthenticated
+	}
+	return TierAnonymous
+}
+
+// waitRateLimit 等待指定等级的限流器放行，ctx 结束时不再等待
+func (c *Client) waitRateLimit(ctx context.Context, tier Tier) error {
+	start := time.Now()
+	if err := c.limiters.get(tier).Wait(ctx); err != nil {
+		return fmt.Errorf("waiting for %s rate limit: %w", tier, err)
+	}
+	metrics.TDXRateLimitWait.WithLabelValues(string(tier)).Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// APIError 是 TDX 返回非 200 状态码时的错误
+type APIError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // 响应的 Retry-After，没有时为 0
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// RateLimited 返回是否因为请求过于频繁而被拒绝
+func (e *APIError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests
+}
+
+// parseRetryAfter 解析 Retry-After 标头，支持秒数与 HTTP 日期两种格式
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -101,19 +101,67 @@ type StationLiveBoard struct {
 
 // 简化的数据结构，用于应用逻辑
 type TrainInfo struct {
-	TrainNo       string
-	TrainType     string
-	ArrivalTime   string
-	DepartureTime string
-	StopSequence  int
-	Stations      []StationInfo
-	Direction     int
-	EndStation    string
+	Operator      Operator      `json:"operator"`
+	TrainNo       string        `json:"train_no"`
+	TrainType     string        `json:"train_type"`
+	TrainTypeCode string        `json:"train_type_code"`
+	ArrivalTime   string        `json:"arrival_time"`
+	DepartureTime string        `json:"departure_time"`
+	StopSequence  int           `json:"stop_sequence,omitempty"`
+	Stations      []StationInfo `json:"stations,omitempty"`
+	Direction     int           `json:"direction"`
+	EndStation    string        `json:"end_station"`
+	Fare          int           `json:"fare,omitempty"` // 成人票价，0 表示未知
+	Bike          bool          `json:"bike"`           // 可携带自行车
+	Wheelchair    bool          `json:"wheelchair"`     // 有无障碍座位
+	Dining        bool          `json:"dining"`         // 提供餐车/订餐服务
+	Platform      string        `json:"platform,omitempty"`
+	DelayMinutes  int           `json:"delay_minutes"`  // 即时看板的延误分钟数
+	RunningStatus int           `json:"running_status"` // 0:准点 1:晚点 2:取消
 }
 
 type StationInfo struct {
-	StationName   string
-	ArrivalTime   string
-	DepartureTime string
-	StopSequence  int
-}
\ No newline at end of file
+	StationName   string `json:"station_name"`
+	ArrivalTime   string `json:"arrival_time"`
+	DepartureTime string `json:"departure_time"`
+	StopSequence  int    `json:"stop_sequence"`
+}
+
+type StationResponse struct {
+	UpdateTime string    `json:"UpdateTime"`
+	Stations   []Station `json:"Stations"`
+}
+
+type ODFareResponse struct {
+	UpdateTime string   `json:"UpdateTime"`
+	ODFares    []ODFare `json:"ODFares"`
+}
+
+type ODFare struct {
+	OriginStationID        string      `json:"OriginStationID"`
+	OriginStationName      StationName `json:"OriginStationName"`
+	DestinationStationID   string      `json:"DestinationStationID"`
+	DestinationStationName StationName `json:"DestinationStationName"`
+	Direction              int         `json:"Direction"`
+	TrainType              int         `json:"TrainType"`
+	Fares                  []Fare      `json:"Fares"`
+	TravelDistance         float64     `json:"TravelDistance"`
+}
+
+type Fare struct {
+	TicketType int `json:"TicketType"`
+	FareClass  int `json:"FareClass"`
+	CabinClass int `json:"CabinClass"`
+	Price      int `json:"Price"`
+}
+
+type DailyTimetableResponse struct {
+	UpdateTime      string                `json:"UpdateTime"`
+	TrainDate       string                `json:"TrainDate"`
+	TrainTimetables []DailyTrainTimetable `json:"TrainTimetables"`
+}
+
+type DailyTrainTimetable struct {
+	TrainInfo GeneralTrainInfo `json:"TrainInfo"`
+	StopTimes []StopTime       `json:"StopTimes"`
+}
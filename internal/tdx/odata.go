@@ -0,0 +1,185 @@
+package tdx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Query 组成 TDX API 的 OData 查询参数。nil 或零值的 Query 只带 $format=JSON。
+// 不合法的字段名称不会立即报错，而是由 Params 返回，请求因此失败
+type Query struct {
+	filter  Expr
+	selects []string
+	orderBy []string
+	top     int
+	skip    int
+	err     error
+}
+
+// NewQuery 建立空的查询
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Filter 设置 $filter，多次调用以 and 合并
+func (q *Query) Filter(expr Expr) *Query {
+	q.filter = And(q.filter, expr)
+	return q
+}
+
+// Select 只取回指定的字段，减少响应大小
+func (q *Query) Select(fields ...string) *Query {
+	for _, field := range fields {
+		q.setErr(checkField(field))
+		q.selects = append(q.selects, field)
+	}
+	return q
+}
+
+// OrderBy 按字段递增排序，可多次调用
+func (q *Query) OrderBy(field string) *Query {
+	q.setErr(checkField(field))
+	q.orderBy = append(q.orderBy, field)
+	return q
+}
+
+// OrderByDesc 按字段递减排序
+func (q *Query) OrderByDesc(field string) *Query {
+	q.setErr(checkField(field))
+	q.orderBy = append(q.orderBy, field+" desc")
+	return q
+}
+
+// Top 最多取回 n 笔，0 表示不限制
+func (q *Query) Top(n int) *Query {
+	q.top = n
+	return q
+}
+
+// Skip 跳过前 n 笔，与 Top 搭配分页
+func (q *Query) Skip(n int) *Query {
+	q.skip = n
+	return q
+}
+
+func (q *Query) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// Params 返回请求的查询参数，查询中有不合法的字段时返回错误
+func (q *Query) Params() (map[string]string, error) {
+	params := map[string]string{"$format": "JSON"}
+	if q == nil {
+		return params, nil
+	}
+	if q.err != nil {
+		return nil, q.err
+	}
+	if q.filter.err != nil {
+		return nil, q.filter.err
+	}
+
+	if !q.filter.empty() {
+		params["$filter"] = q.filter.s
+	}
+	if len(q.selects) > 0 {
+		params["$select"] = strings.Join(q.selects, ",")
+	}
+	if len(q.orderBy) > 0 {
+		params["$orderby"] = strings.Join(q.orderBy, ",")
+	}
+	if q.top > 0 {
+		params["$top"] = strconv.Itoa(q.top)
+	}
+	if q.skip > 0 {
+		params["$skip"] = strconv.Itoa(q.skip)
+	}
+	return params, nil
+}
+
+// Expr 是 $filter 的条件表达式，零值表示没有条件
+type Expr struct {
+	s   string
+	err error
+}
+
+func (e Expr) String() string {
+	return e.s
+}
+
+func (e Expr) empty() bool {
+	return e.s == ""
+}
+
+// EqString 比较字段等于字符串，字符串会加上引号并转义
+func EqString(field, value string) Expr {
+	return newExpr(field, field+" eq "+quote(value))
+}
+
+// EqInt 比较字段等于整数
+func EqInt(field string, value int) Expr {
+	return newExpr(field, field+" eq "+strconv.Itoa(value))
+}
+
+// Contains 判断字段包含子字符串
+func Contains(field, substr string) Expr {
+	return newExpr(field, fmt.Sprintf("contains(%s,%s)", field, quote(substr)))
+}
+
+func newExpr(field, s string) Expr {
+	if err := checkField(field); err != nil {
+		return Expr{err: err}
+	}
+	return Expr{s: s}
+}
+
+// And 以 and 连接条件，忽略空的条件
+func And(exprs ...Expr) Expr {
+	return join("and", exprs)
+}
+
+// Or 以 or 连接条件，忽略空的条件
+func Or(exprs ...Expr) Expr {
+	return join("or", exprs)
+}
+
+func join(op string, exprs []Expr) Expr {
+	var parts []string
+	for _, expr := range exprs {
+		if expr.err != nil {
+			return Expr{err: expr.err}
+		}
+		if !expr.empty() {
+			parts = append(parts, expr.s)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return Expr{}
+	case 1:
+		return Expr{s: parts[0]}
+	}
+	// 加上括号，避免与其他 and/or 组合时优先级出错
+	return Expr{s: "(" + strings.Join(parts, " "+op+" ") + ")"}
+}
+
+// quote 把字符串转换成 OData 常量：以单引号包住，内含的单引号重复一次
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// 字段名称只能是以斜线分隔的识别字，例如 GeneralTimetable/GeneralTrainInfo/TrainNo
+var fieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(/[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// checkField 检查字段名称，避免字段名称被当成表达式的一部分
+func checkField(field string) error {
+	if !fieldPattern.MatchString(field) {
+		return fmt.Errorf("invalid OData field %q", field)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package tdx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	tests := []struct {
+		name string
+		expr Expr
+		want string
+	}{
+		{"string", EqString("StationID", "1180"), "StationID eq '1180'"},
+		{"quote escaped", EqString("StationID", "11'80"), "StationID eq '11''80'"},
+		{"injection stays quoted", EqString("TrainNo", "1' or 1 eq 1 or '"), "TrainNo eq '1'' or 1 eq 1 or '''"},
+		{"int", EqInt("Direction", 1), "Direction eq 1"},
+		{"nested field", EqInt("GeneralTimetable/GeneralTrainInfo/Direction", 0), "GeneralTimetable/GeneralTrainInfo/Direction eq 0"},
+		{"contains", Contains("StationName/Zh_tw", "竹'北"), "contains(StationName/Zh_tw,'竹''北')"},
+		{"and", And(EqString("A", "x"), EqInt("B", 2)), "(A eq 'x' and B eq 2)"},
+		{"or", Or(EqInt("A", 1), EqInt("A", 2), EqInt("A", 3)), "(A eq 1 or A eq 2 or A eq 3)"},
+		{"nested", And(Or(EqInt("A", 1), EqInt("A", 2)), EqString("B", "y")), "((A eq 1 or A eq 2) and B eq 'y')"},
+		{"single is not wrapped", And(EqInt("A", 1)), "A eq 1"},
+		{"empty elided", And(Expr{}, EqInt("A", 1), Expr{}), "A eq 1"},
+		{"all empty", Or(Expr{}, Expr{}), ""},
+		{"no operands", And(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expr.err != nil {
+				t.Fatalf("unexpected error: %v", tt.expr.err)
+			}
+			if got := tt.expr.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  map[string]string
+	}{
+		{"nil", nil, map[string]string{"$format": "JSON"}},
+		{"empty", NewQuery(), map[string]string{"$format": "JSON"}},
+		{
+			"filters are and-ed",
+			NewQuery().Filter(EqString("StationID", "1180")).Filter(EqInt("Direction", 1)),
+			map[string]string{"$format": "JSON", "$filter": "(StationID eq '1180' and Direction eq 1)"},
+		},
+		{
+			"empty filter elided",
+			NewQuery().Filter(Expr{}).Select("StationID"),
+			map[string]string{"$format": "JSON", "$select": "StationID"},
+		},
+		{
+			"all options",
+			NewQuery().
+				Filter(Or(EqString("A", "x"), Contains("B", "y"))).
+				Select("A", "B").
+				OrderBy("A").
+				OrderByDesc("B").
+				Top(10).
+				Skip(20),
+			map[string]string{
+				"$format":  "JSON",
+				"$filter":  "(A eq 'x' or contains(B,'y'))",
+				"$select":  "A,B",
+				"$orderby": "A,B desc",
+				"$top":     "10",
+				"$skip":    "20",
+			},
+		},
+		{"zero top and skip omitted", NewQuery().Top(0).Skip(0), map[string]string{"$format": "JSON"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query.Params()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryInvalidField(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+	}{
+		{"filter", NewQuery().Filter(EqInt("Direction eq 1 or Direction", 0))},
+		{"nested filter", NewQuery().Filter(And(EqInt("A", 1), Or(EqString("B", "x"), Contains("C)", "y"))))},
+		{"select", NewQuery().Select("StationID", "StationName,StationAddress")},
+		{"order by", NewQuery().OrderBy("")},
+		{"order by desc", NewQuery().OrderByDesc("A/")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if params, err := tt.query.Params(); err == nil {
+				t.Errorf("expected error, got %v", params)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package tdx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Operator 是 TDX 轨道资料的营运单位
+type Operator string
+
+const (
+	OperatorTRA  Operator = "TRA"  // 台铁
+	OperatorTHSR Operator = "THSR" // 高铁
+)
+
+// ParseOperator 解析营运单位名称（不区分大小写），空字符串视为台铁
+func ParseOperator(name string) (Operator, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "", string(OperatorTRA):
+		return OperatorTRA, nil
+	case string(OperatorTHSR), "HSR":
+		return OperatorTHSR, nil
+	default:
+		return "", fmt.Errorf("unsupported operator: %s", name)
+	}
+}
+
+// DisplayName 返回营运单位的中文名称
+func (o Operator) DisplayName() string {
+	switch o {
+	case OperatorTHSR:
+		return "高鐵"
+	default:
+		return "臺鐵"
+	}
+}
+
+// defaultBaseURL 推导各营运单位的 API 根路径：高铁资料只提供 v2 版本
+func defaultBaseURL(op Operator, traBaseURL string) string {
+	if op == OperatorTHSR {
+		return strings.Replace(traBaseURL, "/api/basic/v3", "/api/basic/v2", 1)
+	}
+	return traBaseURL
+}
+
+// SetBaseURL 覆盖指定营运单位的 API 根路径
+func (c *Client) SetBaseURL(op Operator, baseURL string) {
+	if baseURL == "" {
+		return
+	}
+	c.baseURLs[op] = baseURL
+}
+
+func (c *Client) operatorURL(op Operator, path string) string {
+	baseURL, ok := c.baseURLs[op]
+	if !ok {
+		baseURL = defaultBaseURL(op, c.baseURL)
+	}
+	return fmt.Sprintf("%s/Rail/%s/%s", baseURL, op, path)
+}
+
+// GetLiveBoard 获取营运单位车站的即时列车信息
+func (c *Client) GetLiveBoard(op Operator, stationID string, direction int) ([]TrainInfo, error) {
+	return c.GetLiveBoardContext(context.Background(), op, stationID, direction)
+}
+
+// GetLiveBoardContext 与 GetLiveBoard 相同，ctx 用于取消请求
+func (c *Client) GetLiveBoardContext(ctx context.Context, op Operator, stationID string, direction int) ([]TrainInfo, error) {
+	if op == OperatorTHSR {
+		return c.thsrLiveBoard(ctx, stationID, direction)
+	}
+	return c.GetTrainTimetableContext(ctx, stationID, direction)
+}
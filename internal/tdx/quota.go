@@ -0,0 +1,71 @@
+package tdx
+
+import (
+	"sync"
+	"time"
+
+	"tg-rail-shouting/internal/metrics"
+)
+
+// 未认证的免费 API 每日限制 50 次请求
+const FreeTierDailyQuota = 50
+
+// quota 统计当天已发出的 TDX 请求数，每天台湾时间午夜重置
+type quota struct {
+	mu    sync.Mutex
+	limit int // 0 表示不限制
+	used  int
+	day   string
+}
+
+func (q *quota) resetIfNewDay() {
+	today := time.Now().In(Location).Format("2006-01-02")
+	if q.day != today {
+		q.day = today
+		q.used = 0
+	}
+}
+
+func (q *quota) record() {
+	q.mu.Lock()
+	q.resetIfNewDay()
+	q.used++
+	q.mu.Unlock()
+
+	metrics.TDXQuotaRemaining.Set(float64(q.remaining()))
+}
+
+// remaining 返回当天剩余的请求数，不限制时返回 -1
+func (q *quota) remaining() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.resetIfNewDay()
+	if q.limit <= 0 {
+		return -1
+	}
+	if q.used >= q.limit {
+		return 0
+	}
+	return q.limit - q.used
+}
+
+// SetDailyQuota 设置每日请求上限，0 表示不限制
+func (c *Client) SetDailyQuota(limit int) {
+	c.quota.mu.Lock()
+	c.quota.limit = limit
+	c.quota.mu.Unlock()
+
+	metrics.TDXQuotaRemaining.Set(float64(c.quota.remaining()))
+}
+
+// QuotaRemaining 返回当天剩余的 TDX 请求数，不限制时返回 -1
+func (c *Client) QuotaRemaining() int {
+	return c.quota.remaining()
+}
+
+// QuotaResetsAt 返回每日请求数下次重置的时间（台湾时间午夜）
+func (c *Client) QuotaResetsAt() time.Time {
+	now := time.Now().In(Location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, Location).AddDate(0, 0, 1)
+}
@@ -0,0 +1,93 @@
+package tdx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const stationCacheTTL = 24 * time.Hour
+
+// stationFields 是 Station 用到的字段，车站资料的地址、电话等其他字段不需要取回
+var stationFields = []string{"StationUID", "StationID", "StationName"}
+
+// GetStations 获取营运单位的全部车站（带缓存）
+func (c *Client) GetStations(op Operator) ([]Station, error) {
+	return c.GetStationsContext(context.Background(), op)
+}
+
+// GetStationsContext 与 GetStations 相同，ctx 用于取消请求
+func (c *Client) GetStationsContext(ctx context.Context, op Operator) ([]Station, error) {
+	cacheKey := "stations:" + string(op)
+	if cached, ok := c.cache.get(cacheKey); ok {
+		return cached.([]Station), nil
+	}
+
+	var stations []Station
+	switch op {
+	case OperatorTHSR:
+		// 高铁 v2 接口直接返回数组
+		if err := c.getJSON(ctx, c.operatorURL(op, "Station"), NewQuery().Select(stationFields...), &stations); err != nil {
+			return nil, fmt.Errorf("failed to get stations: %w", err)
+		}
+	default:
+		var resp StationResponse
+		if err := c.getJSON(ctx, c.operatorURL(op, "Station"), NewQuery().Select(stationFields...), &resp); err != nil {
+			return nil, fmt.Errorf("failed to get stations: %w", err)
+		}
+		stations = resp.Stations
+	}
+
+	c.cache.set(cacheKey, stations, stationCacheTTL)
+	return stations, nil
+}
+
+// FindStation 根据车站代码或名称（中文/英文）查找车站
+func (c *Client) FindStation(op Operator, query string) (*Station, error) {
+	return c.FindStationContext(context.Background(), op, query)
+}
+
+// FindStationContext 与 FindStation 相同，ctx 用于取消请求
+func (c *Client) FindStationContext(ctx context.Context, op Operator, query string) (*Station, error) {
+	matches, err := c.SearchStationsContext(ctx, op, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%s station not found: %s", op, query)
+	}
+	return &matches[0], nil
+}
+
+// SearchStations 返回代码或名称符合 query 的所有车站，精确匹配排在模糊匹配之前
+func (c *Client) SearchStations(op Operator, query string) ([]Station, error) {
+	return c.SearchStationsContext(context.Background(), op, query)
+}
+
+// SearchStationsContext 与 SearchStations 相同，ctx 用于取消请求
+func (c *Client) SearchStationsContext(ctx context.Context, op Operator, query string) ([]Station, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("empty station query")
+	}
+
+	stations, err := c.GetStationsContext(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := strings.ReplaceAll(strings.TrimSuffix(query, "站"), "台", "臺")
+	var exact, partial []Station
+	for _, st := range stations {
+		switch {
+		case st.StationID == query || st.StationName.ZhTw == normalized || strings.EqualFold(st.StationName.En, query):
+			exact = append(exact, st)
+		case strings.Contains(st.StationName.ZhTw, normalized) ||
+			strings.Contains(strings.ToLower(st.StationName.En), strings.ToLower(query)):
+			partial = append(partial, st)
+		}
+	}
+
+	return append(exact, partial...), nil
+}
@@ -0,0 +1,165 @@
+package tdx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 高铁剩余座位状态
+const (
+	SeatAvailable = "O" // 尚有座位
+	SeatLimited   = "L" // 座位有限
+	SeatSoldOut   = "X" // 已售完
+)
+
+type THSRDailyTimetable struct {
+	TrainDate      string        `json:"TrainDate"`
+	DailyTrainInfo THSRTrainInfo `json:"DailyTrainInfo"`
+	StopTimes      []StopTime    `json:"StopTimes"`
+}
+
+type THSRTrainInfo struct {
+	TrainNo             string      `json:"TrainNo"`
+	Direction           int         `json:"Direction"`
+	StartingStationID   string      `json:"StartingStationID"`
+	StartingStationName StationName `json:"StartingStationName"`
+	EndingStationID     string      `json:"EndingStationID"`
+	EndingStationName   StationName `json:"EndingStationName"`
+	Note                StationName `json:"Note"`
+}
+
+// thsrTrainTypeName 高铁没有车种之分，统一显示为高铁
+var thsrTrainTypeName = StationName{ZhTw: "高鐵", En: "THSR"}
+
+// toDailyTrainTimetable 转换成与台铁相同的每日时刻表结构
+func (tt THSRDailyTimetable) toDailyTrainTimetable() DailyTrainTimetable {
+	return DailyTrainTimetable{
+		TrainInfo: GeneralTrainInfo{
+			TrainNo:             tt.DailyTrainInfo.TrainNo,
+			Direction:           tt.DailyTrainInfo.Direction,
+			StartingStationID:   tt.DailyTrainInfo.StartingStationID,
+			StartingStationName: tt.DailyTrainInfo.StartingStationName,
+			EndingStationID:     tt.DailyTrainInfo.EndingStationID,
+			EndingStationName:   tt.DailyTrainInfo.EndingStationName,
+			TrainTypeName:       thsrTrainTypeName,
+			Note:                tt.DailyTrainInfo.Note,
+		},
+		StopTimes: tt.StopTimes,
+	}
+}
+
+type THSRStationSeatsResponse struct {
+	UpdateTime     string             `json:"UpdateTime"`
+	AvailableSeats []THSRStationSeats `json:"AvailableSeats"`
+}
+
+// THSRStationSeats 是车站即时看板上的一班车及其后续各站的剩余座位
+type THSRStationSeats struct {
+	TrainNo           string             `json:"TrainNo"`
+	Direction         int                `json:"Direction"`
+	StationID         string             `json:"StationID"`
+	StationName       StationName        `json:"StationName"`
+	DepartureTime     string             `json:"DepartureTime"`
+	EndingStationID   string             `json:"EndingStationID"`
+	EndingStationName StationName        `json:"EndingStationName"`
+	StopStations      []THSRStopSeatInfo `json:"StopStations"`
+}
+
+type THSRStopSeatInfo struct {
+	StopSequence       int         `json:"StopSequence"`
+	StationID          string      `json:"StationID"`
+	StationName        StationName `json:"StationName"`
+	StandardSeatStatus string      `json:"StandardSeatStatus"`
+	BusinessSeatStatus string      `json:"BusinessSeatStatus"`
+}
+
+type THSRODSeatsResponse struct {
+	UpdateTime     string        `json:"UpdateTime"`
+	AvailableSeats []THSRODSeats `json:"AvailableSeats"`
+}
+
+// THSRODSeats 是某日某起讫站间一班车的剩余座位
+type THSRODSeats struct {
+	TrainDate              string      `json:"TrainDate"`
+	TrainNo                string      `json:"TrainNo"`
+	Direction              int         `json:"Direction"`
+	OriginStationID        string      `json:"OriginStationID"`
+	OriginStationName      StationName `json:"OriginStationName"`
+	DestinationStationID   string      `json:"DestinationStationID"`
+	DestinationStationName StationName `json:"DestinationStationName"`
+	DepartureTime          string      `json:"DepartureTime"`
+	ArrivalTime            string      `json:"ArrivalTime"`
+	StandardSeatStatus     string      `json:"StandardSeatStatus"`
+	BusinessSeatStatus     string      `json:"BusinessSeatStatus"`
+}
+
+// GetTHSRStationSeats 获取高铁车站即时看板，不含各停靠站的剩余座位
+func (c *Client) GetTHSRStationSeats(stationID string) ([]THSRStationSeats, error) {
+	return c.GetTHSRStationSeatsContext(context.Background(), stationID)
+}
+
+// GetTHSRStationSeatsContext 与 GetTHSRStationSeats 相同，ctx 用于取消请求
+func (c *Client) GetTHSRStationSeatsContext(ctx context.Context, stationID string) ([]THSRStationSeats, error) {
+	// 各停靠站的座位状态（StopStations）占了响应的大部分，即时看板用不到
+	query := NewQuery().Select("TrainNo", "Direction", "StationID", "StationName", "DepartureTime", "EndingStationID", "EndingStationName")
+
+	var resp THSRStationSeatsResponse
+	if err := c.getJSON(ctx, c.operatorURL(OperatorTHSR, "AvailableSeatStatusList/"+stationID), query, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get THSR station seats: %w", err)
+	}
+	return resp.AvailableSeats, nil
+}
+
+// GetTHSRAvailableSeats 获取指定日期高铁起讫站间各车次的剩余座位
+func (c *Client) GetTHSRAvailableSeats(originStationID, destinationStationID string, date time.Time) ([]THSRODSeats, error) {
+	return c.GetTHSRAvailableSeatsContext(context.Background(), originStationID, destinationStationID, date)
+}
+
+// GetTHSRAvailableSeatsContext 与 GetTHSRAvailableSeats 相同，ctx 用于取消请求
+func (c *Client) GetTHSRAvailableSeatsContext(ctx context.Context, originStationID, destinationStationID string, date time.Time) ([]THSRODSeats, error) {
+	path := fmt.Sprintf("AvailableSeatStatus/Train/OD/%s/to/%s/TrainDate/%s",
+		originStationID, destinationStationID, date.Format("2006-01-02"))
+
+	query := NewQuery().Select("TrainDate", "TrainNo", "Direction", "OriginStationID", "OriginStationName",
+		"DestinationStationID", "DestinationStationName", "DepartureTime", "ArrivalTime", "StandardSeatStatus", "BusinessSeatStatus")
+
+	var resp THSRODSeatsResponse
+	if err := c.getJSON(ctx, c.operatorURL(OperatorTHSR, path), query, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get THSR available seats: %w", err)
+	}
+	return resp.AvailableSeats, nil
+}
+
+// thsrLiveBoard 高铁不提供延误资料，以车站剩余座位列表作为即时看板
+func (c *Client) thsrLiveBoard(ctx context.Context, stationID string, direction int) ([]TrainInfo, error) {
+	seats, err := c.GetTHSRStationSeatsContext(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var trains []TrainInfo
+	currentTime := time.Now().Format("15:04")
+
+	for _, seat := range seats {
+		if seat.Direction != direction || seat.DepartureTime < currentTime {
+			continue
+		}
+		trains = append(trains, TrainInfo{
+			Operator:      OperatorTHSR,
+			TrainNo:       seat.TrainNo,
+			TrainType:     thsrTrainTypeName.ZhTw,
+			ArrivalTime:   seat.DepartureTime,
+			DepartureTime: seat.DepartureTime,
+			Direction:     seat.Direction,
+			EndStation:    seat.EndingStationName.ZhTw,
+		})
+	}
+
+	sort.Slice(trains, func(i, j int) bool {
+		return trains[i].ArrivalTime < trains[j].ArrivalTime
+	})
+
+	return trains, nil
+}
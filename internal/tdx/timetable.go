@@ -0,0 +1,139 @@
+package tdx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 每日时刻表可能因停驶、加班车而变动，缓存时间不宜过长
+const dailyTimetableCacheTTL = 6 * time.Hour
+
+// GetDailyTimetable 获取营运单位在指定日期全部车次的每日时刻表（带缓存）
+func (c *Client) GetDailyTimetable(op Operator, date time.Time) ([]DailyTrainTimetable, error) {
+	return c.GetDailyTimetableContext(context.Background(), op, date)
+}
+
+// GetDailyTimetableContext 与 GetDailyTimetable 相同，ctx 用于取消请求
+func (c *Client) GetDailyTimetableContext(ctx context.Context, op Operator, date time.Time) ([]DailyTrainTimetable, error) {
+	trainDate := date.Format("2006-01-02")
+	cacheKey := fmt.Sprintf("daily:%s:%s", op, trainDate)
+	if cached, ok := c.cache.get(cacheKey); ok {
+		return cached.([]DailyTrainTimetable), nil
+	}
+
+	var timetables []DailyTrainTimetable
+	switch op {
+	case OperatorTHSR:
+		var resp []THSRDailyTimetable
+		if err := c.getJSON(ctx, c.operatorURL(op, "DailyTimetable/TrainDate/"+trainDate), NewQuery().Select("TrainDate", "DailyTrainInfo", "StopTimes"), &resp); err != nil {
+			return nil, fmt.Errorf("failed to get daily timetable: %w", err)
+		}
+		for _, tt := range resp {
+			timetables = append(timetables, tt.toDailyTrainTimetable())
+		}
+	default:
+		var resp DailyTimetableResponse
+		if err := c.getJSON(ctx, c.operatorURL(op, "DailyTrainTimetable/TrainDate/"+trainDate), NewQuery().Select("TrainInfo", "StopTimes"), &resp); err != nil {
+			return nil, fmt.Errorf("failed to get daily timetable: %w", err)
+		}
+		timetables = resp.TrainTimetables
+	}
+
+	c.cache.set(cacheKey, timetables, dailyTimetableCacheTTL)
+	return timetables, nil
+}
+
+// GetRoute 获取车次的停靠站：台铁使用定期时刻表，高铁使用当天的每日时刻表
+func (c *Client) GetRoute(op Operator, trainNo string) ([]StationInfo, error) {
+	return c.GetRouteContext(context.Background(), op, trainNo)
+}
+
+// GetRouteContext 与 GetRoute 相同，ctx 用于取消请求
+func (c *Client) GetRouteContext(ctx context.Context, op Operator, trainNo string) ([]StationInfo, error) {
+	if op == OperatorTRA {
+		return c.GetTrainRouteContext(ctx, trainNo)
+	}
+
+	timetables, err := c.GetDailyTimetableContext(ctx, op, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tt := range timetables {
+		if tt.TrainInfo.TrainNo != trainNo {
+			continue
+		}
+		stations := make([]StationInfo, 0, len(tt.StopTimes))
+		for _, st := range tt.StopTimes {
+			stations = append(stations, StationInfo{
+				StationName:   st.StationName.ZhTw,
+				ArrivalTime:   st.ArrivalTime,
+				DepartureTime: st.DepartureTime,
+				StopSequence:  st.StopSequence,
+			})
+		}
+		return stations, nil
+	}
+
+	return nil, fmt.Errorf("train not found: %s", trainNo)
+}
+
+// Location 是 TDX 时刻表使用的时区（台湾时间）
+var Location = loadLocation()
+
+func loadLocation() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Taipei"); err == nil {
+		return loc
+	}
+	return time.FixedZone("CST", 8*60*60)
+}
+
+// StopTimeOn 将时刻表中的 "HH:MM"（或 "HH:MM:SS"）换算成 day 当天的时间
+func StopTimeOn(day time.Time, value string) (time.Time, bool) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, day.Location()), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// TrainInfoAt 返回车次在指定车站的列车信息，Stations 为该站之后的停靠站
+func (tt DailyTrainTimetable) TrainInfoAt(stationID string) (TrainInfo, bool) {
+	for _, st := range tt.StopTimes {
+		if st.StationID != stationID {
+			continue
+		}
+
+		arrivalTime := st.ArrivalTime
+		if arrivalTime == "" {
+			arrivalTime = st.DepartureTime
+		}
+
+		info := TrainInfo{
+			TrainNo:       tt.TrainInfo.TrainNo,
+			TrainType:     tt.TrainInfo.TrainTypeName.ZhTw,
+			TrainTypeCode: tt.TrainInfo.TrainTypeCode,
+			ArrivalTime:   arrivalTime,
+			DepartureTime: st.DepartureTime,
+			StopSequence:  st.StopSequence,
+			Direction:     tt.TrainInfo.Direction,
+			EndStation:    tt.TrainInfo.EndingStationName.ZhTw,
+		}
+		info.applyAmenities(tt.TrainInfo)
+
+		for _, next := range tt.StopTimes {
+			if next.StopSequence >= st.StopSequence {
+				info.Stations = append(info.Stations, StationInfo{
+					StationName:   next.StationName.ZhTw,
+					ArrivalTime:   next.ArrivalTime,
+					DepartureTime: next.DepartureTime,
+					StopSequence:  next.StopSequence,
+				})
+			}
+		}
+		return info, true
+	}
+	return TrainInfo{}, false
+}
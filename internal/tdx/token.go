@@ -0,0 +1,229 @@
+package tdx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/metrics"
+	"tg-rail-shouting/internal/redact"
+)
+
+const (
+	tokenExpiryMargin = time.Minute     // 距离过期不到这段时间的 token 视为已过期
+	tokenRefreshAhead = 5 * time.Minute // 背景刷新比过期提前的时间
+	authRetryDelay    = time.Minute     // 认证失败后这段时间内改用免费 API，不再重试
+)
+
+// tokenState 保存 access token，所有字段由 mu 保护
+type tokenState struct {
+	mu         sync.Mutex
+	token      string
+	expiry     time.Time
+	retryAt    time.Time     // 认证失败后，在此之前不再尝试
+	refreshing chan struct{} // 不为 nil 表示正在刷新，刷新结束时关闭
+	freeTier   sync.Once
+}
+
+// valid 返回 token 在 now 时是否仍然可用，调用方需持有锁
+func (t *tokenState) valid(now time.Time) bool {
+	return t.token != "" && now.Before(t.expiry.Add(-tokenExpiryMargin))
+}
+
+func (c *Client) hasCredentials() bool {
+	return c.clientID != "" && c.clientSecret != ""
+}
+
+// accessToken 返回可用的 access token，过期时先刷新。
+// 没有认证信息或认证失败时返回空字符串，请求改用免费 API
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	if !c.hasCredentials() {
+		c.tokens.freeTier.Do(func() {
+			logrus.Info("Using TDX API without authentication (free tier)")
+		})
+		return "", nil
+	}
+
+	c.tokens.mu.Lock()
+	now := time.Now()
+	token, valid := c.tokens.token, c.tokens.valid(now)
+	backoff := now.Before(c.tokens.retryAt)
+	c.tokens.mu.Unlock()
+
+	if valid {
+		return token, nil
+	}
+	if backoff {
+		return "", nil
+	}
+
+	if err := c.refreshToken(ctx); err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	c.tokens.mu.Lock()
+	defer c.tokens.mu.Unlock()
+	return c.tokens.token, nil
+}
+
+// refreshToken 向 TDX 取得新的 access token。同一时间只会发出一个认证请求，
+// 其他调用者等待该请求的结果
+func (c *Client) refreshToken(ctx context.Context) error {
+	c.tokens.mu.Lock()
+	if done := c.tokens.refreshing; done != nil {
+		c.tokens.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			return nil
+		}
+	}
+	done := make(chan struct{})
+	c.tokens.refreshing = done
+	c.tokens.mu.Unlock()
+
+	token, expiry, err := c.requestToken(ctx)
+
+	c.tokens.mu.Lock()
+	switch {
+	case err == nil:
+		c.tokens.token, c.tokens.expiry = token, expiry
+		c.tokens.retryAt = time.Time{}
+	case ctx.Err() == nil:
+		// 认证失败：旧 token 仍然可用时继续使用，否则改用免费 API
+		if !c.tokens.valid(time.Now()) {
+			c.tokens.token = ""
+		}
+		c.tokens.retryAt = time.Now().Add(authRetryDelay)
+	}
+	c.tokens.refreshing = nil
+	c.tokens.mu.Unlock()
+	close(done)
+
+	return err
+}
+
+// requestToken 以 client credentials 向 TDX 认证服务取得 access token
+func (c *Client) requestToken(ctx context.Context) (string, time.Time, error) {
+	logrus.Info("Authenticating with TDX API...")
+
+	reqCtx, cancel := withTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.client.R().
+		SetContext(reqCtx).
+		SetHeader("Content-Type", "application/x-www-form-urlencoded").
+		SetFormData(map[string]string{
+			"grant_type":    "client_credentials",
+			"client_id":     c.clientID,
+			"client_secret": c.clientSecret,
+		}).
+		Post(c.authURL)
+
+	if err != nil {
+		// 调用方取消时不算认证失败
+		if ctx.Err() != nil {
+			return "", time.Time{}, ctx.Err()
+		}
+		logrus.WithError(err).Warn("Authentication request failed, falling back to free API")
+		metrics.TDXTokenRefreshes.WithLabelValues("failure").Inc()
+		return "", time.Time{}, err
+	}
+
+	if resp.StatusCode() != 200 {
+		metrics.TDXTokenRefreshes.WithLabelValues("failure").Inc()
+		logrus.WithFields(logrus.Fields{
+			"status": resp.StatusCode(),
+			"body":   truncate(redact.String(resp.String()), 200),
+		}).Warn("Authentication failed, falling back to free API")
+		return "", time.Time{}, fmt.Errorf("authentication failed with status: %d", resp.StatusCode())
+	}
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(resp.Body(), &tokenResp); err != nil || tokenResp.AccessToken == "" {
+		logrus.WithError(err).Warn("Failed to parse token response, falling back to free API")
+		metrics.TDXTokenRefreshes.WithLabelValues("failure").Inc()
+		return "", time.Time{}, fmt.Errorf("invalid token response: %v", err)
+	}
+
+	redact.Register(tokenResp.AccessToken)
+	metrics.TDXTokenRefreshes.WithLabelValues("success").Inc()
+	logrus.Info("TDX API authentication successful")
+
+	return tokenResp.AccessToken, time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second), nil
+}
+
+// invalidateToken 丢弃被 TDX 拒绝的 token；已被其他请求换成新 token 时不处理
+func (c *Client) invalidateToken(token string) {
+	c.tokens.mu.Lock()
+	defer c.tokens.mu.Unlock()
+
+	if c.tokens.token == token {
+		c.tokens.token = ""
+		c.tokens.expiry = time.Time{}
+	}
+}
+
+// RefreshTokens 在背景中于 access token 过期前刷新，直到 ctx 取消，
+// 使请求不必等待认证。没有认证信息时直接返回
+func (c *Client) RefreshTokens(ctx context.Context) {
+	if !c.hasCredentials() {
+		return
+	}
+
+	for {
+		timer := time.NewTimer(c.nextTokenRefresh(time.Now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := c.refreshToken(ctx); err != nil && ctx.Err() == nil {
+			logrus.WithError(err).Warn("Background TDX token refresh failed")
+		}
+	}
+}
+
+// nextTokenRefresh 返回距离下一次背景刷新的时间
+func (c *Client) nextTokenRefresh(now time.Time) time.Duration {
+	c.tokens.mu.Lock()
+	defer c.tokens.mu.Unlock()
+
+	if now.Before(c.tokens.retryAt) {
+		return c.tokens.retryAt.Sub(now)
+	}
+	if c.tokens.token == "" {
+		return 0
+	}
+
+	// 有效期很短的 token 在剩余时间过半时刷新
+	ahead := tokenRefreshAhead
+	if remaining := c.tokens.expiry.Sub(now); remaining < 2*ahead {
+		ahead = remaining / 2
+	}
+	if wait := c.tokens.expiry.Add(-ahead).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// TokenStatus 返回是否使用认证 API 以及 access token 的过期时间
+func (c *Client) TokenStatus() (bool, time.Time) {
+	if !c.hasCredentials() {
+		return false, time.Time{}
+	}
+
+	c.tokens.mu.Lock()
+	defer c.tokens.mu.Unlock()
+
+	if c.tokens.token == "" {
+		return false, time.Time{}
+	}
+	return true, c.tokens.expiry
+}
@@ -0,0 +1,95 @@
+// Package tdxmock 提供模拟的 TDX 台铁 API，按令牌桶限流并在超过时返回 429，
+// 供 railctl probe-rate-limit 在不消耗真实请求数的情况下测试限流
+package tdxmock
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"tg-rail-shouting/internal/ratelimit"
+	"tg-rail-shouting/internal/tdx"
+)
+
+// Server 是模拟 TDX API 的 http.Handler，路径与 TDX 的 API 根路径之后相同，例如 /Rail/TRA/StationLiveBoard
+type Server struct {
+	limiter  *ratelimit.Limiter
+	requests atomic.Int64
+	limited  atomic.Int64
+}
+
+// New 建立每秒最多接受 rate 次、最多连续 burst 次请求的模拟服务器
+func New(rate float64, burst int) *Server {
+	return &Server{limiter: ratelimit.New(rate, burst)}
+}
+
+// Stats 返回收到的请求数与其中被限流拒绝的次数
+func (s *Server) Stats() (requests, limited int64) {
+	return s.requests.Load(), s.limited.Load()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+
+	if !s.limiter.Allow() {
+		s.limited.Add(1)
+		// Retry-After 只能是整数秒，至少 1 秒
+		retryAfter := int(math.Ceil(s.limiter.Delay().Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"message": "API rate limit exceeded"})
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/Rail/TRA/StationLiveBoard"):
+		writeJSON(w, http.StatusOK, liveBoard())
+	case strings.HasSuffix(r.URL.Path, "/Rail/TRA/Station"):
+		writeJSON(w, http.StatusOK, tdx.StationResponse{
+			UpdateTime: time.Now().In(tdx.Location).Format(time.RFC3339),
+			Stations:   []tdx.Station{station},
+		})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "not found"})
+	}
+}
+
+var station = tdx.Station{
+	StationUID:  "TRA-1180",
+	StationID:   "1180",
+	StationName: tdx.StationName{ZhTw: "竹北", En: "Zhubei"},
+}
+
+// liveBoard 返回一小时后出发的一班列车
+func liveBoard() tdx.StationLiveBoardResponse {
+	now := time.Now().In(tdx.Location)
+	departure := now.Add(time.Hour).Format("15:04")
+
+	return tdx.StationLiveBoardResponse{
+		UpdateTime: now.Format(time.RFC3339),
+		StationLiveBoards: []tdx.StationLiveBoard{{
+			StationID:             station.StationID,
+			StationName:           station.StationName,
+			TrainNo:               "1234",
+			Direction:             1,
+			TrainTypeCode:         "6",
+			TrainTypeName:         tdx.StationName{ZhTw: "區間", En: "Local Train"},
+			EndingStationName:     tdx.StationName{ZhTw: "新竹", En: "Hsinchu"},
+			ScheduleArrivalTime:   departure,
+			ScheduleDepartureTime: departure,
+			UpdateTime:            now,
+		}},
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
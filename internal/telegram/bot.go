@@ -1,33 +1,77 @@
 package telegram
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/health"
+	"tg-rail-shouting/internal/httpclient"
+	"tg-rail-shouting/internal/metrics"
+	"tg-rail-shouting/internal/redact"
 	"tg-rail-shouting/internal/tdx"
 )
 
+// defaultSendTimeout 是调用方没有设置期限时，发送一则消息的超时时间
+const defaultSendTimeout = 15 * time.Second
+
 type Bot struct {
-	client *resty.Client
-	token  string
-	chatID string
+	client   *resty.Client
+	token    string
+	chatID   string
+	commands commandRegistry
+
+	sendTimeout time.Duration
 }
 
 func NewBot(token, chatID string) *Bot {
 	return &Bot{
-		client: resty.New(),
-		token:  token,
-		chatID: chatID,
+		client:   httpclient.Default(),
+		token:    token,
+		chatID:   chatID,
+		commands: commandRegistry{handlers: make(map[string]CommandHandler)},
+
+		sendTimeout: defaultSendTimeout,
+	}
+}
+
+// SetTransport 按 opts 设置代理、CA、超时与 User-Agent，需在发出请求前调用
+func (b *Bot) SetTransport(opts httpclient.Options) error {
+	client, err := httpclient.New(opts)
+	if err != nil {
+		return err
 	}
+	b.client = client
+	if opts.ReadTimeout > 0 {
+		b.sendTimeout = opts.ReadTimeout
+	}
+	return nil
 }
 
 func (b *Bot) SendMessage(text string) error {
+	return b.SendMessageContext(context.Background(), text)
+}
+
+// SendMessageContext 与 SendMessage 相同，ctx 用于取消请求
+func (b *Bot) SendMessageContext(ctx context.Context, text string) error {
+	err := b.sendMessage(ctx, text)
+	b.recordSend(ctx, err)
+	return err
+}
+
+func (b *Bot) sendMessage(ctx context.Context, text string) error {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", b.token)
 	
+	ctx, cancel := withTimeout(ctx, b.sendTimeout)
+	defer cancel()
+
 	resp, err := b.client.R().
+		SetContext(ctx).
 		SetHeader("Content-Type", "application/json").
 		SetBody(map[string]interface{}{
 			"chat_id":    b.chatID,
@@ -37,7 +81,7 @@ func (b *Bot) SendMessage(text string) error {
 		Post(url)
 
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return fmt.Errorf("failed to send message: %w", redact.Error(err))
 	}
 
 	if resp.StatusCode() != 200 {
@@ -48,25 +92,94 @@ func (b *Bot) SendMessage(text string) error {
 	return nil
 }
 
-func (b *Bot) SendTrainInfo(trains []tdx.TrainInfo, stationName string) error {
+// SendPhoto 发送 PNG 图片，caption 使用 HTML 格式
+func (b *Bot) SendPhoto(photo []byte, caption string) error {
+	return b.SendPhotoContext(context.Background(), photo, caption)
+}
+
+// SendPhotoContext 与 SendPhoto 相同，ctx 用于取消请求
+func (b *Bot) SendPhotoContext(ctx context.Context, photo []byte, caption string) error {
+	err := b.sendPhoto(ctx, photo, caption)
+	b.recordSend(ctx, err)
+	return err
+}
+
+// recordSend 记录发送结果，调用方取消的发送不计入
+func (b *Bot) recordSend(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	metrics.TelegramMessages.WithLabelValues(metrics.Result(err)).Inc()
+	health.RecordTelegramSend(err)
+}
+
+// withTimeout 在调用方没有设置期限时套用 timeout
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (b *Bot) sendPhoto(ctx context.Context, photo []byte, caption string) error {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendPhoto", b.token)
+
+	ctx, cancel := withTimeout(ctx, b.sendTimeout)
+	defer cancel()
+
+	resp, err := b.client.R().
+		SetContext(ctx).
+		SetFormData(map[string]string{
+			"chat_id":    b.chatID,
+			"caption":    caption,
+			"parse_mode": "HTML",
+		}).
+		SetFileReader("photo", "chart.png", bytes.NewReader(photo)).
+		Post(url)
+
+	if err != nil {
+		return fmt.Errorf("failed to send photo: %w", redact.Error(err))
+	}
+
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("telegram API error: %d, body: %s", resp.StatusCode(), resp.String())
+	}
+
+	logrus.Info("Photo sent successfully to Telegram")
+	return nil
+}
+
+// SendTrainInfo 发送车站列车信息，highlight 不为空时显示在列表最上方
+func (b *Bot) SendTrainInfo(trains []tdx.TrainInfo, stationName, highlight string) error {
+	return b.SendTrainInfoContext(context.Background(), trains, stationName, highlight)
+}
+
+// SendTrainInfoContext 与 SendTrainInfo 相同，ctx 用于取消请求
+func (b *Bot) SendTrainInfoContext(ctx context.Context, trains []tdx.TrainInfo, stationName, highlight string) error {
 	if len(trains) == 0 {
 		message := fmt.Sprintf("🚄 %s站 列车信息\n\n暂无列车信息", stationName)
-		return b.SendMessage(message)
+		return b.SendMessageContext(ctx, message)
 	}
 
 	var message strings.Builder
 	message.WriteString(fmt.Sprintf("🚄 %s站 列车信息\n\n", stationName))
+	if highlight != "" {
+		message.WriteString(highlight + "\n\n")
+	}
 
 	for i, train := range trains {
 		if i >= 5 {
 			break
 		}
 
-		message.WriteString(fmt.Sprintf("🚂 %s次 (%s)\n", train.TrainNo, train.TrainType))
+		message.WriteString(fmt.Sprintf("🚂 %s次 (%s)%s\n", train.TrainNo, train.TrainType, amenityIcons(train)))
 		message.WriteString(fmt.Sprintf("⏰ 到达: %s", train.ArrivalTime))
 		if train.DepartureTime != "" && train.DepartureTime != train.ArrivalTime {
 			message.WriteString(fmt.Sprintf(" / 出发: %s", train.DepartureTime))
 		}
+		if train.Fare > 0 {
+			message.WriteString(fmt.Sprintf(" 💰 NT$%d", train.Fare))
+		}
 		message.WriteString("\n\n")
 
 		if len(train.Stations) > 0 {
@@ -87,13 +200,18 @@ func (b *Bot) SendTrainInfo(trains []tdx.TrainInfo, stationName string) error {
 		message.WriteString("\n")
 	}
 
-	return b.SendMessage(message.String())
+	return b.SendMessageContext(ctx, message.String())
 }
 
 func (b *Bot) SendDetailedTrainInfo(trains []tdx.TrainInfo, stationName string, targetStation string) error {
+	return b.SendDetailedTrainInfoContext(context.Background(), trains, stationName, targetStation)
+}
+
+// SendDetailedTrainInfoContext 与 SendDetailedTrainInfo 相同，ctx 用于取消请求
+func (b *Bot) SendDetailedTrainInfoContext(ctx context.Context, trains []tdx.TrainInfo, stationName string, targetStation string) error {
 	if len(trains) == 0 {
 		message := fmt.Sprintf("🚄 <b>%s站 → %s 列车信息</b>\n\n暂无列车信息", stationName, targetStation)
-		return b.SendMessage(message)
+		return b.SendMessageContext(ctx, message)
 	}
 
 	var message strings.Builder
@@ -105,7 +223,7 @@ func (b *Bot) SendDetailedTrainInfo(trains []tdx.TrainInfo, stationName string,
 			break
 		}
 
-		message.WriteString(fmt.Sprintf("🚂 <b>%s次 (%s)</b>\n", train.TrainNo, train.TrainType))
+		message.WriteString(fmt.Sprintf("🚂 <b>%s次 (%s)</b>%s\n", train.TrainNo, train.TrainType, amenityIcons(train)))
 		message.WriteString(fmt.Sprintf("⏰ 到达: %s", train.ArrivalTime))
 		if train.DepartureTime != "" && train.DepartureTime != train.ArrivalTime {
 			message.WriteString(fmt.Sprintf(" / 出发: %s", train.DepartureTime))
@@ -138,10 +256,15 @@ func (b *Bot) SendDetailedTrainInfo(trains []tdx.TrainInfo, stationName string,
 		message.WriteString("\n")
 	}
 
-	return b.SendMessage(message.String())
+	return b.SendMessageContext(ctx, message.String())
 }
 
 func (b *Bot) SendStartupMessage() error {
+	return b.SendStartupMessageContext(context.Background())
+}
+
+// SendStartupMessageContext 与 SendStartupMessage 相同，ctx 用于取消请求
+func (b *Bot) SendStartupMessageContext(ctx context.Context) error {
 	version := b.getVersion()
 	message := fmt.Sprintf("🚀 <b>台灣鐵路監控服務啟動成功</b>\n\n"+
 		"✅ Telegram Bot 連線正常\n"+
@@ -150,7 +273,7 @@ func (b *Bot) SendStartupMessage() error {
 		"🔄 檢查間隔: 每30分鐘\n\n"+
 		"📋 版本: v%s", version)
 	
-	return b.SendMessage(message)
+	return b.SendMessageContext(ctx, message)
 }
 
 func (b *Bot) getVersion() string {
@@ -162,6 +285,24 @@ func (b *Bot) getVersion() string {
 	return strings.TrimSpace(string(content))
 }
 
+// amenityIcons 返回列车服务的图示：🚲 自行车、♿ 无障碍、🍱 餐车
+func amenityIcons(train tdx.TrainInfo) string {
+	var icons string
+	if train.Bike {
+		icons += "🚲"
+	}
+	if train.Wheelchair {
+		icons += "♿"
+	}
+	if train.Dining {
+		icons += "🍱"
+	}
+	if icons == "" {
+		return ""
+	}
+	return " " + icons
+}
+
 func getCurrentTime() string {
 	return fmt.Sprintf("%s", "现在")
 }
\ No newline at end of file
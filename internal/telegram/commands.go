@@ -0,0 +1,205 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"tg-rail-shouting/internal/redact"
+)
+
+// CommandHandler 处理一条聊天指令，返回要回复的 HTML 文本；ctx 在停止轮询时取消
+type CommandHandler func(ctx context.Context, args []string) (string, error)
+
+type commandRegistry struct {
+	mu       sync.RWMutex
+	handlers map[string]CommandHandler
+}
+
+type Update struct {
+	UpdateID int      `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+type Message struct {
+	MessageID int    `json:"message_id"`
+	Text      string `json:"text"`
+	Chat      Chat   `json:"chat"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type updatesResponse struct {
+	OK          bool     `json:"ok"`
+	Result      []Update `json:"result"`
+	Description string   `json:"description"`
+}
+
+// 长轮询超时时间（秒）
+const pollTimeoutSeconds = 30
+
+// 同时处理的指令数上限，以及单一指令的处理时间上限
+const (
+	maxConcurrentCommands = 4
+	commandTimeout        = 2 * time.Minute
+)
+
+// HandleCommand 注册一个指令处理函数，name 不带斜线，例如 "fare"
+func (b *Bot) HandleCommand(name string, handler CommandHandler) {
+	b.commands.mu.Lock()
+	defer b.commands.mu.Unlock()
+
+	b.commands.handlers[strings.TrimPrefix(name, "/")] = handler
+}
+
+// Poll 通过 getUpdates 长轮询接收指令，直到 ctx 被取消。
+// 指令在背景处理，较慢的指令（例如 /plan）不会阻塞轮询与其他指令；返回前等待处理中的指令结束
+func (b *Bot) Poll(ctx context.Context) {
+	logrus.Info("Telegram command polling started")
+	offset := 0
+
+	slots := make(chan struct{}, maxConcurrentCommands)
+	var handlers sync.WaitGroup
+	defer handlers.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("Telegram command polling stopped")
+			return
+		default:
+		}
+
+		updates, err := b.getUpdates(ctx, offset)
+		if ctx.Err() != nil {
+			logrus.Info("Telegram command polling stopped")
+			return
+		}
+		if err != nil {
+			logrus.WithError(err).Warn("Failed to get Telegram updates")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+
+			// 处理中的指令已达上限时等待空位
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				logrus.Info("Telegram command polling stopped")
+				return
+			}
+			handlers.Add(1)
+			go func(update Update) {
+				defer func() {
+					<-slots
+					handlers.Done()
+				}()
+				b.handleUpdate(ctx, update)
+			}(update)
+		}
+	}
+}
+
+func (b *Bot) getUpdates(ctx context.Context, offset int) ([]Update, error) {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates", b.token)
+
+	// 长轮询本身会等待 pollTimeoutSeconds，另外留出读取响应的时间
+	ctx, cancel := withTimeout(ctx, pollTimeoutSeconds*time.Second+b.sendTimeout)
+	defer cancel()
+
+	resp, err := b.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"offset":          fmt.Sprintf("%d", offset),
+			"timeout":         fmt.Sprintf("%d", pollTimeoutSeconds),
+			"allowed_updates": `["message"]`,
+		}).
+		Get(url)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get updates: %w", redact.Error(err))
+	}
+
+	var result updatesResponse
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse updates: %w", err)
+	}
+
+	if !result.OK {
+		return nil, fmt.Errorf("telegram API error: %d, %s", resp.StatusCode(), result.Description)
+	}
+
+	return result.Result, nil
+}
+
+func (b *Bot) handleUpdate(ctx context.Context, update Update) {
+	msg := update.Message
+	if msg == nil || !strings.HasPrefix(msg.Text, "/") {
+		return
+	}
+
+	// 只接受配置的聊天发出的指令
+	if fmt.Sprintf("%d", msg.Chat.ID) != b.chatID {
+		logrus.WithField("chat", msg.Chat.ID).Warn("Ignoring command from unknown chat")
+		return
+	}
+
+	fields := strings.Fields(msg.Text)
+	// 群组中的指令可能带有 @botname 后缀
+	name := strings.TrimPrefix(strings.SplitN(fields[0], "@", 2)[0], "/")
+
+	b.commands.mu.RLock()
+	handler, ok := b.commands.handlers[name]
+	b.commands.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"command": name,
+		"args":    fields[1:],
+	}).Info("Handling Telegram command")
+
+	handlerCtx, cancel := context.WithTimeout(ctx, commandTimeout)
+	reply, err := handler(handlerCtx, fields[1:])
+	timedOut := errors.Is(handlerCtx.Err(), context.DeadlineExceeded)
+	cancel()
+
+	switch {
+	case ctx.Err() != nil:
+		// 停止轮询时不再回复
+		return
+	case err != nil && timedOut:
+		logrus.WithError(err).WithField("command", name).Warn("Telegram command timed out")
+		reply = fmt.Sprintf("⏱️ /%s 处理超时，请稍后再试", escapeHTML(name))
+	case err != nil:
+		reply = fmt.Sprintf("❌ %s", escapeHTML(redact.String(err.Error())))
+	}
+
+	if reply == "" {
+		return
+	}
+
+	if err := b.SendMessageContext(ctx, reply); err != nil {
+		logrus.WithError(err).WithField("command", name).Error("Failed to send command reply")
+	}
+}
+
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
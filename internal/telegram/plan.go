@@ -0,0 +1,55 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"tg-rail-shouting/internal/planner"
+)
+
+// FormatItineraries 将行程规划结果排成 HTML 文本
+func FormatItineraries(itineraries []planner.Itinerary, origin, destination string) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("🗺️ <b>%s → %s 行程规划</b>\n\n", origin, destination))
+
+	if len(itineraries) == 0 {
+		message.WriteString("暂无可用行程")
+		return message.String()
+	}
+
+	for i, it := range itineraries {
+		transfers := "直达"
+		if it.Transfers() > 0 {
+			transfers = fmt.Sprintf("转乘%d次", it.Transfers())
+		}
+		message.WriteString(fmt.Sprintf("%d. <b>%s → %s</b> (%d分钟, %s)\n",
+			i+1,
+			it.Departure().Format("15:04"),
+			it.Arrival().Format("15:04"),
+			int(it.Duration().Minutes()),
+			transfers))
+
+		for _, leg := range it.Legs {
+			message.WriteString(fmt.Sprintf("    🚂 %s次 (%s) %s %s → %s %s\n",
+				leg.TrainNo,
+				leg.TrainType,
+				leg.FromStationName,
+				leg.Departure.Format("15:04"),
+				leg.ToStationName,
+				leg.Arrival.Format("15:04")))
+		}
+		message.WriteString("\n")
+	}
+
+	return message.String()
+}
+
+func (b *Bot) SendItineraries(itineraries []planner.Itinerary, origin, destination string) error {
+	return b.SendItinerariesContext(context.Background(), itineraries, origin, destination)
+}
+
+// SendItinerariesContext 与 SendItineraries 相同，ctx 用于取消请求
+func (b *Bot) SendItinerariesContext(ctx context.Context, itineraries []planner.Itinerary, origin, destination string) error {
+	return b.SendMessageContext(ctx, FormatItineraries(itineraries, origin, destination))
+}
@@ -0,0 +1,54 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tg-rail-shouting/internal/planner"
+)
+
+// Recommendation 是根据历史延误推荐的车次
+type Recommendation struct {
+	Leg         planner.Leg
+	ArriveBy    time.Time
+	Probability float64 // 历史上能在 ArriveBy 前到达的比例
+	Samples     int     // 有记录的天数
+	Confident   bool    // 是否达到要求的信心水准
+}
+
+// FormatRecommendationLine 是列车信息中标示推荐车次的一行
+func FormatRecommendationLine(rec Recommendation) string {
+	icon := "⭐"
+	if !rec.Confident {
+		icon = "⚠️"
+	}
+	return fmt.Sprintf("%s <b>推荐 %s次 %s 出发</b>，历史上 %.0f%% 可在 %s 前到达%s",
+		icon,
+		escapeHTML(rec.Leg.TrainNo),
+		rec.Leg.Departure.Format("15:04"),
+		rec.Probability*100,
+		rec.ArriveBy.Format("15:04"),
+		escapeHTML(rec.Leg.ToStationName))
+}
+
+// FormatRecommendation 将 /recommend 的结果排成 HTML 文本
+func FormatRecommendation(rec *Recommendation, origin, destination string, arriveBy time.Time, confidence int) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("🎯 <b>%s → %s，%s 前到达</b>\n\n", origin, destination, arriveBy.Format("15:04")))
+
+	if rec == nil {
+		message.WriteString("没有延误记录足够的直达车次（需要监控起站才会记录延误）")
+		return message.String()
+	}
+
+	if !rec.Confident {
+		message.WriteString(fmt.Sprintf("⚠️ 没有车次能达到 %d%% 的把握，以下是把握最高的车次\n\n", confidence))
+	}
+	message.WriteString(fmt.Sprintf("🚂 %s次 (%s)\n", escapeHTML(rec.Leg.TrainNo), rec.Leg.TrainType))
+	message.WriteString(fmt.Sprintf("⏰ %s 出发 → %s 到达\n", rec.Leg.Departure.Format("15:04"), rec.Leg.Arrival.Format("15:04")))
+	message.WriteString(fmt.Sprintf("📊 有记录的 %d 天中 %.0f%% 可准时到达\n", rec.Samples, rec.Probability*100))
+	message.WriteString("ℹ️ 按起站的历史出发延误估计到达时间")
+
+	return message.String()
+}
@@ -0,0 +1,104 @@
+package telegram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tg-rail-shouting/internal/store"
+)
+
+// 每周报告中最差、最佳各列出几个车次
+const reportTopTrains = 3
+
+// ReportSection 是每周报告中一个监控配置档（起讫站）的统计
+type ReportSection struct {
+	Title     string
+	Trains    []store.TrainStats
+	Hours     []store.HourStats
+	Cancelled []store.Observation
+}
+
+// FormatWeeklyReport 将每周准点报告排成 HTML 文本，表格以 <pre> 等宽显示
+func FormatWeeklyReport(from, to time.Time, sections []ReportSection) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("📈 <b>每周准点报告</b>\n%s ~ %s\n", from.Format("01/02"), to.Format("01/02")))
+
+	for _, section := range sections {
+		message.WriteString(fmt.Sprintf("\n🚉 <b>%s</b>\n", escapeHTML(section.Title)))
+
+		worst, best := rankTrains(section.Trains)
+		if len(worst) == 0 {
+			message.WriteString("本周暂无记录\n")
+		} else {
+			message.WriteString("\n🐢 最常误点\n")
+			writeTrainTable(&message, worst)
+			if len(best) > 0 {
+				message.WriteString("\n🚀 最准点\n")
+				writeTrainTable(&message, best)
+			}
+		}
+
+		if len(section.Hours) > 0 {
+			message.WriteString("\n🕖 各时段平均延误\n<pre>")
+			message.WriteString("时段   准点%  平均  P90\n")
+			for _, hour := range section.Hours {
+				message.WriteString(fmt.Sprintf("%02d:00 %5.0f %5.1f %4d\n",
+					hour.Hour, hour.Punctuality.OnTimePercent, hour.Punctuality.AvgDelay, hour.Punctuality.P90Delay))
+			}
+			message.WriteString("</pre>")
+		}
+
+		if len(section.Cancelled) > 0 {
+			message.WriteString("\n❌ 停驶\n")
+			for _, obs := range section.Cancelled {
+				message.WriteString(fmt.Sprintf("%s %s次 (%s)\n", obs.Date, escapeHTML(obs.TrainNo), obs.ScheduledTime))
+			}
+		}
+	}
+
+	message.WriteString(fmt.Sprintf("\n准点指延误未满%d分钟", store.OnTimeThresholdMinutes))
+	return message.String()
+}
+
+// rankTrains 选出准点率最低与最高的车次，两组不会重复
+func rankTrains(trains []store.TrainStats) (worst, best []store.TrainStats) {
+	var ran []store.TrainStats
+	for _, train := range trains {
+		if train.Punctuality.Count > 0 {
+			ran = append(ran, train)
+		}
+	}
+
+	sort.SliceStable(ran, func(i, j int) bool {
+		a, b := ran[i].Punctuality, ran[j].Punctuality
+		if a.OnTimePercent != b.OnTimePercent {
+			return a.OnTimePercent < b.OnTimePercent
+		}
+		return a.AvgDelay > b.AvgDelay
+	})
+
+	// 车次不多时前一半列为最差、后一半列为最佳
+	n := (len(ran) + 1) / 2
+	if n > reportTopTrains {
+		n = reportTopTrains
+	}
+	worst = ran[:n]
+
+	for i := len(ran) - 1; i >= n && len(best) < reportTopTrains; i-- {
+		best = append(best, ran[i])
+	}
+	return worst, best
+}
+
+func writeTrainTable(message *strings.Builder, trains []store.TrainStats) {
+	message.WriteString("<pre>")
+	message.WriteString("车次   准点%  平均  P90 天数\n")
+	for _, train := range trains {
+		p := train.Punctuality
+		message.WriteString(fmt.Sprintf("%-6s %5.0f %5.1f %4d %4d\n",
+			escapeHTML(train.TrainNo), p.OnTimePercent, p.AvgDelay, p.P90Delay, p.Count))
+	}
+	message.WriteString("</pre>")
+}
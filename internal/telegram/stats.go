@@ -0,0 +1,49 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tg-rail-shouting/internal/store"
+)
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "周一",
+	time.Tuesday:   "周二",
+	time.Wednesday: "周三",
+	time.Thursday:  "周四",
+	time.Friday:    "周五",
+	time.Saturday:  "周六",
+	time.Sunday:    "周日",
+}
+
+// FormatPunctuality 将车次的准点统计排成 HTML 文本
+func FormatPunctuality(trainNo string, weeks int, byWeekday []store.WeekdayStats, overall store.Punctuality) string {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("📊 <b>%s次 最近%d周准点统计</b>\n\n", escapeHTML(trainNo), weeks))
+
+	if overall.Count == 0 && overall.Cancelled == 0 {
+		message.WriteString("暂无记录")
+		return message.String()
+	}
+
+	for _, day := range byWeekday {
+		message.WriteString(fmt.Sprintf("%s: %s\n", weekdayNames[day.Weekday], formatPunctualityLine(day.Punctuality)))
+	}
+	message.WriteString(fmt.Sprintf("\n<b>合计</b>: %s\n", formatPunctualityLine(overall)))
+	message.WriteString(fmt.Sprintf("\n准点指延误未满%d分钟", store.OnTimeThresholdMinutes))
+
+	return message.String()
+}
+
+func formatPunctualityLine(p store.Punctuality) string {
+	line := "无行驶记录"
+	if p.Count > 0 {
+		line = fmt.Sprintf("准点 %.0f%% | 平均晚 %.1f 分 | P90 %d 分 (%d天)", p.OnTimePercent, p.AvgDelay, p.P90Delay, p.Count)
+	}
+	if p.Cancelled > 0 {
+		line += fmt.Sprintf(" | 停驶 %d 次", p.Cancelled)
+	}
+	return line
+}
@@ -2,66 +2,24 @@ package main
 
 import (
 	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/sirupsen/logrus"
-	"tg-rail-shouting/internal/config"
-	"tg-rail-shouting/internal/monitor"
-	"tg-rail-shouting/internal/tdx"
-	"tg-rail-shouting/internal/telegram"
+	"tg-rail-shouting/internal/app"
 )
 
 func main() {
-	setupLogger()
-	
-	logrus.Info("Starting TG Rail Shouting service...")
-	
-	cfg, err := config.Load()
-	if err != nil {
-		logrus.WithError(err).Fatal("Failed to load configuration")
+	// 容器 HEALTHCHECK 使用：./main healthcheck [url]
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		os.Exit(app.Healthcheck(os.Args[2:]))
 	}
-	
-	tdxClient := tdx.NewClient(
-		cfg.TDX.ClientID,
-		cfg.TDX.ClientSecret,
-		cfg.TDX.BaseURL,
-		cfg.TDX.AuthURL,
-	)
-	
-	tgBot := telegram.NewBot(cfg.Telegram.BotToken, cfg.Telegram.ChatID)
-	
-	// Send startup message with version
-	if err := tgBot.SendStartupMessage(); err != nil {
-		logrus.WithError(err).Warn("Failed to send startup message")
+	// 检查配置：./main config validate [配置文件]
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "validate" {
+		os.Exit(app.ValidateConfig(os.Args[3:]))
 	}
-	
-	scheduler := monitor.NewScheduler(cfg, tdxClient, tgBot)
-	
-	if err := scheduler.SendTestMessage(); err != nil {
-		logrus.WithError(err).Warn("Failed to send test message")
-	}
-	
-	if err := scheduler.Start(); err != nil {
-		logrus.WithError(err).Fatal("Failed to start scheduler")
+
+	app.SetupLogger()
+
+	if err := app.Serve(); err != nil {
+		logrus.WithError(err).Fatal("Service failed")
 	}
-	
-	logrus.Info("Service started successfully")
-	
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-	
-	<-stop
-	logrus.Info("Received shutdown signal")
-	
-	scheduler.Stop()
-	logrus.Info("Service stopped")
 }
-
-func setupLogger() {
-	logrus.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-		TimestampFormat: "2006-01-02 15:04:05",
-	})
-	logrus.SetLevel(logrus.InfoLevel)
-}
\ No newline at end of file